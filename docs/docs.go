@@ -17,18 +17,28 @@ const docTemplate = `{
     "paths": {
         "/barangays": {
             "get": {
-                "description": "get Barangays",
+                "description": "get Barangays. With per_page=all the whole list is streamed, without metadata.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
                     "Barangays"
                 ],
                 "summary": "Show list of Barangays",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "example": "keyword",
@@ -36,6 +46,17 @@ const docTemplate = `{
                         "name": "keyword",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
                     {
                         "minimum": 0,
                         "type": "integer",
@@ -43,12 +64,63 @@ const docTemplate = `{
                         "name": "page",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
                     {
                         "maximum": 1000,
                         "type": "integer",
                         "example": 1000,
                         "name": "per_page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "U",
+                            "R"
+                        ],
+                        "type": "string",
+                        "description": "U for urban and R for rural barangays",
+                        "name": "urban_rural",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Pob."
+                        ],
+                        "type": "string",
+                        "description": "Pob. for the poblacions",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, citmun_code, name, urban_rural, status, population_2015, population_2020",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -61,13 +133,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -80,7 +152,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
                     "Barangays"
@@ -93,6 +168,18 @@ const docTemplate = `{
                         "name": "psgc_code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -105,19 +192,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Item Not Found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -125,18 +212,28 @@ const docTemplate = `{
         },
         "/citi_muni": {
             "get": {
-                "description": "get Cities/Municipalities",
+                "description": "get Cities/Municipalities. With per_page=all the whole list is streamed, without metadata.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
                     "Cities/Municipalities"
                 ],
                 "summary": "Show list of Cities/Municipalities",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "example": "keyword",
@@ -144,6 +241,17 @@ const docTemplate = `{
                         "name": "keyword",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
                     {
                         "minimum": 0,
                         "type": "integer",
@@ -151,12 +259,79 @@ const docTemplate = `{
                         "name": "page",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
                     {
                         "maximum": 1000,
                         "type": "integer",
                         "example": 1000,
                         "name": "per_page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CC",
+                            "HUC",
+                            "ICC"
+                        ],
+                        "type": "string",
+                        "description": "City class, CC for component, HUC for highly urbanized and ICC for independent component cities",
+                        "name": "city_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Capital"
+                        ],
+                        "type": "string",
+                        "description": "Capital for the provincial capitals",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, prov_code, name, level, city_class, income_class, status, population_2015, population_2020",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -169,13 +344,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -188,7 +363,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
                     "Cities/Municipalities"
@@ -201,6 +379,18 @@ const docTemplate = `{
                         "name": "psgc_code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -213,19 +403,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Item Not Found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -233,18 +423,28 @@ const docTemplate = `{
         },
         "/cities": {
             "get": {
-                "description": "get Cities",
+                "description": "get Cities. With per_page=all the whole list is streamed, without metadata.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
                     "Cities"
                 ],
                 "summary": "Show list of Cities",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "example": "keyword",
@@ -252,6 +452,17 @@ const docTemplate = `{
                         "name": "keyword",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
                     {
                         "minimum": 0,
                         "type": "integer",
@@ -259,12 +470,79 @@ const docTemplate = `{
                         "name": "page",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
                     {
                         "maximum": 1000,
                         "type": "integer",
                         "example": 1000,
                         "name": "per_page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CC",
+                            "HUC",
+                            "ICC"
+                        ],
+                        "type": "string",
+                        "description": "City class, CC for component, HUC for highly urbanized and ICC for independent component cities",
+                        "name": "city_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Capital"
+                        ],
+                        "type": "string",
+                        "description": "Capital for the provincial capitals",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, prov_code, name, level, city_class, income_class, status, population_2015, population_2020",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -277,13 +555,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -296,7 +574,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
                     "Cities"
@@ -309,6 +590,18 @@ const docTemplate = `{
                         "name": "psgc_code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -321,19 +614,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Item Not Found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -341,18 +634,28 @@ const docTemplate = `{
         },
         "/municipalities": {
             "get": {
-                "description": "get Municipalities",
+                "description": "get Municipalities. With per_page=all the whole list is streamed, without metadata.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
                     "Municipalities"
                 ],
                 "summary": "Show list of Municipalities",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "example": "keyword",
@@ -360,6 +663,17 @@ const docTemplate = `{
                         "name": "keyword",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
                     {
                         "minimum": 0,
                         "type": "integer",
@@ -367,12 +681,68 @@ const docTemplate = `{
                         "name": "page",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
                     {
                         "maximum": 1000,
                         "type": "integer",
                         "example": 1000,
                         "name": "per_page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Capital"
+                        ],
+                        "type": "string",
+                        "description": "Capital for the provincial capitals",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, prov_code, name, level, city_class, income_class, status, population_2015, population_2020",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -385,13 +755,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -404,7 +774,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
                     "Municipalities"
@@ -417,6 +790,18 @@ const docTemplate = `{
                         "name": "psgc_code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -429,32 +814,108 @@ const docTemplate = `{
                     "400": {
                         "description": "Item Not Found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/provinces": {
+        "/options": {
             "get": {
-                "description": "get Provinces",
+                "description": "get the units of a level right below a parent as value/label pairs sorted by label, e.g. level=Prov\u0026parent=0700000000. The list isn't paginated, a level without a parent is only allowed for regions and provinces.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "Options"
+                ],
+                "summary": "Show the options of a select widget",
+                "parameters": [
+                    {
+                        "enum": [
+                            "Reg",
+                            "Prov",
+                            "City",
+                            "Mun",
+                            "SGU",
+                            "Bgy"
+                        ],
+                        "type": "string",
+                        "description": "Geographic level of the options",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PsgcCode of the parent",
+                        "name": "parent",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/Option"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Parent Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/provinces": {
+            "get": {
+                "description": "get Provinces. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
                 "tags": [
                     "Provinces"
                 ],
                 "summary": "Show list of Provinces",
                 "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "example": "keyword",
@@ -462,6 +923,17 @@ const docTemplate = `{
                         "name": "keyword",
                         "in": "query"
                     },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
                     {
                         "minimum": 0,
                         "type": "integer",
@@ -469,12 +941,59 @@ const docTemplate = `{
                         "name": "page",
                         "in": "query"
                     },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
                     {
                         "maximum": 1000,
                         "type": "integer",
                         "example": 1000,
                         "name": "per_page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, reg_code, name, income_class, population_2015, population_2020",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -487,13 +1006,13 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -506,7 +1025,10 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
                     "Provinces"
@@ -519,6 +1041,18 @@ const docTemplate = `{
                         "name": "psgc_code",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -531,127 +1065,2451 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "404": {
                         "description": "Item Not Found",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/regions": {
+        "/psgc/{psgc_code}": {
             "get": {
-                "description": "get Regions",
+                "description": "get a unit of any level by PsgcCode",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
-                    "Regions"
+                    "PSGC"
                 ],
-                "summary": "Show list of Regions",
+                "summary": "Show a geographic unit",
                 "parameters": [
                     {
                         "type": "string",
-                        "example": "keyword",
-                        "description": "Keyword is used for filtering",
-                        "name": "keyword",
-                        "in": "query"
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "minimum": 0,
-                        "type": "integer",
-                        "example": 1,
-                        "name": "page",
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
                         "in": "query"
-                    },
-                    {
-                        "maximum": 1000,
-                        "type": "integer",
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/GeoUnit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/psgc/{psgc_code}/descendants": {
+            "get": {
+                "description": "get every unit of a level under any ancestor, e.g. all barangays of a province. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "PSGC"
+                ],
+                "summary": "Show the descendants of a geographic unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ancestor PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "Prov",
+                            "City",
+                            "Mun",
+                            "SGU",
+                            "Bgy"
+                        ],
+                        "type": "string",
+                        "description": "Geographic level of the descendants",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "keyword",
+                        "description": "Keyword is used for filtering",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "example": 1000,
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, name, level, parent_code, population_2015, population_2020",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PaginatedGeoUnit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/psgc/{psgc_code}/stats": {
+            "get": {
+                "description": "get the number of units of each level below a unit, its 2015 and 2020 populations with the annual growth rate, and the urban/rural split of its barangays",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "PSGC"
+                ],
+                "summary": "Show the stats of a geographic unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Stats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/psgc/{psgc_code}/tree": {
+            "get": {
+                "description": "get a unit and the units below it as one nested document, e.g. region → provinces → cities/municipalities → barangays. The tree is streamed and gzip compressed when the client accepts it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "PSGC"
+                ],
+                "summary": "Show the tree of a geographic unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Root PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 3,
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 3,
+                        "description": "Levels below the root to include",
+                        "name": "depth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/TreeNode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/rankings": {
+            "get": {
+                "description": "get the units of a level ranked by a metric, e.g. level=City\u0026metric=population_2020\u0026limit=20 for the 20 most populous cities, or level=Mun\u0026metric=growth_rate\u0026scope=1100000000 for the fastest-growing municipalities of Region XI. Tied units share a rank, limit is the last rank returned so every unit tied at it is included.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "Rankings"
+                ],
+                "summary": "Show the top units of a level",
+                "parameters": [
+                    {
+                        "enum": [
+                            "Reg",
+                            "Prov",
+                            "City",
+                            "Mun",
+                            "SGU",
+                            "Bgy"
+                        ],
+                        "type": "string",
+                        "description": "Geographic level of the units",
+                        "name": "level",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "population_2015",
+                            "population_2020",
+                            "population_change",
+                            "growth_rate"
+                        ],
+                        "type": "string",
+                        "default": "population_2020",
+                        "description": "Metric to rank by",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "PsgcCode of the unit the ranked units are below, the whole country by default",
+                        "name": "scope",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "default": "desc",
+                        "description": "desc for the highest first, asc for the lowest first",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Last rank",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/RankingResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions": {
+            "get": {
+                "description": "get Regions. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "Regions"
+                ],
+                "summary": "Show list of Regions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "keyword",
+                        "description": "Keyword is used for filtering",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "example": 1000,
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, name, population_2015, population_2020",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PaginatedRegion"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/regions/{psgc_code}": {
+            "get": {
+                "description": "get string by PsgcCode",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "Regions"
+                ],
+                "summary": "Show a Region",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Region PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Region"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "get the number of units of each level, the 2015 and 2020 populations with the annual growth rate, and the urban/rural split of the barangays of the whole country",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "Stats"
+                ],
+                "summary": "Show the stats of the country",
+                "parameters": [
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/Stats"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/suggest": {
+            "get": {
+                "description": "get the units with a word of their name starting with q, e.g. q=taguig\u0026level=City,Bgy. Matching ignores case, diacritics and punctuation. The upper levels come first, then the most populous units.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suggest"
+                ],
+                "summary": "Suggest units by name prefix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Prefix of a word of the name",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated geographic levels",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 50,
+                        "minimum": 1,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Number of suggestions",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/GeoUnit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/barangays": {
+            "get": {
+                "description": "get the units of a level, every level in the same shape. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show list of units of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "keyword",
+                        "description": "Keyword is used for filtering",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "example": 1000,
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CC",
+                            "HUC",
+                            "ICC"
+                        ],
+                        "type": "string",
+                        "description": "City class of cities/municipalities and cities",
+                        "name": "city_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class of provinces, cities and municipalities",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "U",
+                            "R"
+                        ],
+                        "type": "string",
+                        "description": "U for urban and R for rural barangays",
+                        "name": "urban_rural",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Capital",
+                            "Pob."
+                        ],
+                        "type": "string",
+                        "description": "Capital for the provincial capitals, Pob. for the poblacions",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. level eq 'City' and population_2020 gt 500000, over the fields of Unit the level has",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PaginatedUnit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/barangays/{psgc_code}": {
+            "get": {
+                "description": "get a unit by PsgcCode, in the data envelope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show a unit of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UnitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/cities": {
+            "get": {
+                "description": "get the units of a level, every level in the same shape. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show list of units of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "keyword",
+                        "description": "Keyword is used for filtering",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "example": 1000,
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CC",
+                            "HUC",
+                            "ICC"
+                        ],
+                        "type": "string",
+                        "description": "City class of cities/municipalities and cities",
+                        "name": "city_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class of provinces, cities and municipalities",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "U",
+                            "R"
+                        ],
+                        "type": "string",
+                        "description": "U for urban and R for rural barangays",
+                        "name": "urban_rural",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Capital",
+                            "Pob."
+                        ],
+                        "type": "string",
+                        "description": "Capital for the provincial capitals, Pob. for the poblacions",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. level eq 'City' and population_2020 gt 500000, over the fields of Unit the level has",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PaginatedUnit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/cities-municipalities": {
+            "get": {
+                "description": "get the units of a level, every level in the same shape. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show list of units of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "keyword",
+                        "description": "Keyword is used for filtering",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "example": 1000,
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CC",
+                            "HUC",
+                            "ICC"
+                        ],
+                        "type": "string",
+                        "description": "City class of cities/municipalities and cities",
+                        "name": "city_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class of provinces, cities and municipalities",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "U",
+                            "R"
+                        ],
+                        "type": "string",
+                        "description": "U for urban and R for rural barangays",
+                        "name": "urban_rural",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Capital",
+                            "Pob."
+                        ],
+                        "type": "string",
+                        "description": "Capital for the provincial capitals, Pob. for the poblacions",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. level eq 'City' and population_2020 gt 500000, over the fields of Unit the level has",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PaginatedUnit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/cities-municipalities/{psgc_code}": {
+            "get": {
+                "description": "get a unit by PsgcCode, in the data envelope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show a unit of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UnitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/cities/{psgc_code}": {
+            "get": {
+                "description": "get a unit by PsgcCode, in the data envelope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show a unit of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UnitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/municipalities": {
+            "get": {
+                "description": "get the units of a level, every level in the same shape. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show list of units of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "keyword",
+                        "description": "Keyword is used for filtering",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "example": 1000,
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CC",
+                            "HUC",
+                            "ICC"
+                        ],
+                        "type": "string",
+                        "description": "City class of cities/municipalities and cities",
+                        "name": "city_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class of provinces, cities and municipalities",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "U",
+                            "R"
+                        ],
+                        "type": "string",
+                        "description": "U for urban and R for rural barangays",
+                        "name": "urban_rural",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Capital",
+                            "Pob."
+                        ],
+                        "type": "string",
+                        "description": "Capital for the provincial capitals, Pob. for the poblacions",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. level eq 'City' and population_2020 gt 500000, over the fields of Unit the level has",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PaginatedUnit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/municipalities/{psgc_code}": {
+            "get": {
+                "description": "get a unit by PsgcCode, in the data envelope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show a unit of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UnitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/provinces": {
+            "get": {
+                "description": "get the units of a level, every level in the same shape. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show list of units of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "keyword",
+                        "description": "Keyword is used for filtering",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "example": 1000,
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CC",
+                            "HUC",
+                            "ICC"
+                        ],
+                        "type": "string",
+                        "description": "City class of cities/municipalities and cities",
+                        "name": "city_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class of provinces, cities and municipalities",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "U",
+                            "R"
+                        ],
+                        "type": "string",
+                        "description": "U for urban and R for rural barangays",
+                        "name": "urban_rural",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Capital",
+                            "Pob."
+                        ],
+                        "type": "string",
+                        "description": "Capital for the provincial capitals, Pob. for the poblacions",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. level eq 'City' and population_2020 gt 500000, over the fields of Unit the level has",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PaginatedUnit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/provinces/{psgc_code}": {
+            "get": {
+                "description": "get a unit by PsgcCode, in the data envelope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show a unit of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UnitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/psgc/{psgc_code}": {
+            "get": {
+                "description": "get a unit of any level by PsgcCode, in the data envelope. Units of the unified hierarchy don't carry the attributes of their level.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show a geographic unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UnitResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/psgc/{psgc_code}/descendants": {
+            "get": {
+                "description": "get every unit of a level under any ancestor, e.g. all barangays of a province. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show the descendants of a geographic unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Ancestor PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "Prov",
+                            "City",
+                            "Mun",
+                            "SGU",
+                            "Bgy"
+                        ],
+                        "type": "string",
+                        "description": "Geographic level of the descendants",
+                        "name": "level",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "keyword",
+                        "description": "Keyword is used for filtering",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
                         "example": 1000,
                         "name": "per_page",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, name, level, parent_code, population_2015, population_2020",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/PaginatedRegion"
+                            "$ref": "#/definitions/PaginatedUnit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/psgc/{psgc_code}/stats": {
+            "get": {
+                "description": "get the number of units of each level below a unit, its 2015 and 2020 populations with the annual growth rate, and the urban/rural split of its barangays, in the data envelope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show the stats of a geographic unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/StatsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/psgc/{psgc_code}/tree": {
+            "get": {
+                "description": "get a unit and the units below it as one nested document, every node a Unit with its children. The tree is streamed and gzip compressed when the client accepts it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show the tree of a geographic unit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Root PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "maximum": 3,
+                        "minimum": 0,
+                        "type": "integer",
+                        "default": 3,
+                        "description": "Levels below the root to include",
+                        "name": "depth",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UnitTreeNode"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/regions": {
+            "get": {
+                "description": "get the units of a level, every level in the same shape. With per_page=all the whole list is streamed, without metadata.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show list of units of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "",
+                        "description": "Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "keyword",
+                        "description": "Keyword is used for filtering",
+                        "name": "keyword",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "example": "asc",
+                        "description": "Order is the sort direction",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "minimum": 0,
+                        "type": "integer",
+                        "example": 1,
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "0700000000",
+                        "description": "Parent limits the list to the children of a psgc_code, it isn't used for regions",
+                        "name": "parent",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 1000,
+                        "type": "integer",
+                        "example": 1000,
+                        "name": "per_page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "psgc_code",
+                        "description": "Sort is the field used for ordering, each resource has its own set of sortable fields",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "CC",
+                            "HUC",
+                            "ICC"
+                        ],
+                        "type": "string",
+                        "description": "City class of cities/municipalities and cities",
+                        "name": "city_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1st",
+                            "2nd",
+                            "3rd",
+                            "4th",
+                            "5th",
+                            "6th",
+                            "Special"
+                        ],
+                        "type": "string",
+                        "description": "Income class of provinces, cities and municipalities",
+                        "name": "income_class",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "U",
+                            "R"
+                        ],
+                        "type": "string",
+                        "description": "U for urban and R for rural barangays",
+                        "name": "urban_rural",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "Capital",
+                            "Pob."
+                        ],
+                        "type": "string",
+                        "description": "Capital for the provincial capitals, Pob. for the poblacions",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. level eq 'City' and population_2020 gt 500000, over the fields of Unit the level has",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/PaginatedUnit"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/v2/regions/{psgc_code}": {
+            "get": {
+                "description": "get a unit by PsgcCode, in the data envelope",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
+                ],
+                "tags": [
+                    "v2"
+                ],
+                "summary": "Show a unit of a level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "PsgcCode",
+                        "name": "psgc_code",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
+                        "type": "string",
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/UnitResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Item Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
             }
         },
-        "/regions/{psgc_code}": {
+        "/v2/stats": {
             "get": {
-                "description": "get string by PsgcCode",
+                "description": "get the number of units of each level, the 2015 and 2020 populations with the annual growth rate, and the urban/rural split of the barangays of the whole country, in the data envelope",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson",
+                    "text/xml"
                 ],
                 "tags": [
-                    "Regions"
+                    "v2"
                 ],
-                "summary": "Show a Region",
+                "summary": "Show the stats of the country",
                 "parameters": [
                     {
+                        "enum": [
+                            "json",
+                            "csv",
+                            "ndjson",
+                            "xml"
+                        ],
                         "type": "string",
-                        "description": "Region PsgcCode",
-                        "name": "psgc_code",
-                        "in": "path",
-                        "required": true
+                        "description": "Response format, overrides the Accept header",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/Region"
+                            "$ref": "#/definitions/StatsResponse"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "404": {
-                        "description": "Item Not Found",
-                        "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "type": "string"
+                            "$ref": "#/definitions/Problem"
                         }
                     }
                 }
@@ -668,23 +3526,95 @@ const docTemplate = `{
                 "name": {
                     "type": "string"
                 },
+                "population_2015": {
+                    "type": "integer"
+                },
+                "population_2020": {
+                    "type": "integer"
+                },
                 "psgc_code": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "urban_rural": {
+                    "type": "string"
                 }
             }
         },
         "CityMuni": {
             "type": "object",
             "properties": {
+                "city_class": {
+                    "type": "string"
+                },
+                "income_class": {
+                    "type": "string"
+                },
                 "level": {
                     "type": "string"
                 },
                 "name": {
                     "type": "string"
                 },
+                "population_2015": {
+                    "type": "integer"
+                },
+                "population_2020": {
+                    "type": "integer"
+                },
                 "prov_code": {
                     "type": "string"
                 },
+                "psgc_code": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "FieldError": {
+            "type": "object",
+            "properties": {
+                "constraint": {
+                    "type": "string",
+                    "example": "lte=1000"
+                },
+                "field": {
+                    "type": "string",
+                    "example": "per_page"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "per_page should be less than 1000."
+                },
+                "position": {
+                    "description": "Position is where the error is in a filter expression, from 1",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "GeoUnit": {
+            "type": "object",
+            "properties": {
+                "level": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_code": {
+                    "type": "string"
+                },
+                "population_2015": {
+                    "type": "integer"
+                },
+                "population_2020": {
+                    "type": "integer"
+                },
                 "psgc_code": {
                     "type": "string"
                 }
@@ -693,10 +3623,23 @@ const docTemplate = `{
         "MetaData": {
             "type": "object",
             "properties": {
+                "has_next": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "has_prev": {
+                    "type": "boolean",
+                    "example": false
+                },
                 "item_count": {
                     "type": "integer",
                     "example": 1000
                 },
+                "next_cursor": {
+                    "description": "NextCursor and PrevCursor continue the list from the last and first item of this page",
+                    "type": "string",
+                    "example": "eyJzIjoicHNnY19jb2RlIiwibyI6ImFzYyIsImsiOiIwMTAwMDAwMDAwIn0"
+                },
                 "page": {
                     "type": "integer",
                     "example": 1
@@ -705,6 +3648,10 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1000
                 },
+                "prev_cursor": {
+                    "type": "string",
+                    "example": "eyJzIjoicHNnY19jb2RlIiwibyI6ImFzYyIsImsiOiIwMTAwMDAwMDAwIiwiYiI6dHJ1ZX0"
+                },
                 "total_items": {
                     "type": "integer",
                     "example": 10000
@@ -715,6 +3662,17 @@ const docTemplate = `{
                 }
             }
         },
+        "Option": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
         "PaginatedBarangay": {
             "type": "object",
             "properties": {
@@ -743,6 +3701,20 @@ const docTemplate = `{
                 }
             }
         },
+        "PaginatedGeoUnit": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/GeoUnit"
+                    }
+                },
+                "metadata": {
+                    "$ref": "#/definitions/MetaData"
+                }
+            }
+        },
         "PaginatedProvince": {
             "type": "object",
             "properties": {
@@ -771,12 +3743,80 @@ const docTemplate = `{
                 }
             }
         },
+        "PaginatedUnit": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/Unit"
+                    }
+                },
+                "metadata": {
+                    "$ref": "#/definitions/MetaData"
+                }
+            }
+        },
+        "Problem": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "description": "Code is a stable identifier of the kind of error",
+                    "type": "string",
+                    "example": "invalid_parameter"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "per_page should be less than 1000."
+                },
+                "details": {
+                    "description": "Details lists the invalid parameters of a validation error",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/FieldError"
+                    }
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/api/regions"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "per_page should be less than 1000."
+                },
+                "request_id": {
+                    "type": "string",
+                    "example": "host/abcdef-000001"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Bad Request"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
         "Province": {
             "type": "object",
             "properties": {
+                "income_class": {
+                    "type": "string"
+                },
                 "name": {
                     "type": "string"
                 },
+                "population_2015": {
+                    "type": "integer"
+                },
+                "population_2020": {
+                    "type": "integer"
+                },
                 "psgc_code": {
                     "type": "string"
                 },
@@ -785,14 +3825,235 @@ const docTemplate = `{
                 }
             }
         },
+        "RankedUnit": {
+            "type": "object",
+            "properties": {
+                "growth_rate": {
+                    "type": "number"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_code": {
+                    "type": "string"
+                },
+                "population_2015": {
+                    "type": "integer"
+                },
+                "population_2020": {
+                    "type": "integer"
+                },
+                "population_change": {
+                    "type": "integer"
+                },
+                "psgc_code": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                }
+            }
+        },
+        "RankingResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/RankedUnit"
+                    }
+                }
+            }
+        },
         "Region": {
             "type": "object",
             "properties": {
                 "name": {
                     "type": "string"
                 },
+                "population_2015": {
+                    "type": "integer"
+                },
+                "population_2020": {
+                    "type": "integer"
+                },
+                "psgc_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "Stats": {
+            "type": "object",
+            "properties": {
+                "barangays": {
+                    "type": "integer"
+                },
+                "cities": {
+                    "type": "integer"
+                },
+                "growth_rate": {
+                    "description": "GrowthRate is the annual population growth rate from 2015 to 2020 in\npercent, 0 without a 2015 population",
+                    "type": "number"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "municipalities": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "population_2015": {
+                    "type": "integer"
+                },
+                "population_2020": {
+                    "type": "integer"
+                },
+                "population_change": {
+                    "type": "integer"
+                },
+                "provinces": {
+                    "type": "integer"
+                },
+                "psgc_code": {
+                    "type": "string"
+                },
+                "regions": {
+                    "type": "integer"
+                },
+                "rural_barangays": {
+                    "type": "integer"
+                },
+                "rural_population_2020": {
+                    "type": "integer"
+                },
+                "sgus": {
+                    "type": "integer"
+                },
+                "urban_barangays": {
+                    "description": "Barangays without an urban/rural classification are in neither split",
+                    "type": "integer"
+                },
+                "urban_population_2020": {
+                    "type": "integer"
+                }
+            }
+        },
+        "StatsResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/Stats"
+                }
+            }
+        },
+        "TreeNode": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/TreeNode"
+                    }
+                },
+                "level": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "psgc_code": {
+                    "type": "string"
+                }
+            }
+        },
+        "Unit": {
+            "type": "object",
+            "properties": {
+                "city_class": {
+                    "type": "string"
+                },
+                "income_class": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_code": {
+                    "description": "ParentCode is the unit right above, empty for regions. Independent\ncities, Pateros and SGUs are right below their region.",
+                    "type": "string"
+                },
+                "population_2015": {
+                    "type": "integer"
+                },
+                "population_2020": {
+                    "type": "integer"
+                },
+                "psgc_code": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "urban_rural": {
+                    "type": "string"
+                }
+            }
+        },
+        "UnitResponse": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/Unit"
+                }
+            }
+        },
+        "UnitTreeNode": {
+            "type": "object",
+            "properties": {
+                "children": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/UnitTreeNode"
+                    }
+                },
+                "city_class": {
+                    "type": "string"
+                },
+                "income_class": {
+                    "type": "string"
+                },
+                "level": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parent_code": {
+                    "description": "ParentCode is the unit right above, empty for regions. Independent\ncities, Pateros and SGUs are right below their region.",
+                    "type": "string"
+                },
+                "population_2015": {
+                    "type": "integer"
+                },
+                "population_2020": {
+                    "type": "integer"
+                },
                 "psgc_code": {
                     "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "urban_rural": {
+                    "type": "string"
                 }
             }
         }
@@ -2,10 +2,11 @@ package api
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/render"
 	"github.com/Brix101/psgc-tool/internal/util"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -24,7 +25,7 @@ func (rs bryResource) Routes() chi.Router {
 	r := chi.NewRouter()
 	// r.Use() // some middleware..
 
-	r.With(util.Paginate).Get("/", rs.List) // GET /barangays - read a list of barangays
+	r.With(util.PaginateAll(domain.BarangaySortFields...), util.Filter(domain.BarangayAttributes...), util.FilterExpr(domain.BarangayFilterFields)).Get("/", rs.List) // GET /barangays - read a list of barangays
 
 	r.Route("/{psgc_code}", func(r chi.Router) {
 		r.Use(rs.BarangayCtx) // lets have a barangays map, and lets actually load/manipulate
@@ -41,7 +42,7 @@ func (rs bryResource) BarangayCtx(next http.Handler) http.Handler {
 
 		item, err := rs.bgyRepo.GetById(ctx, psgcCode)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			repositoryError(w, r, rs.logger, err, "failed to fetch barangay from database")
 			return
 		}
 
@@ -53,39 +54,50 @@ func (rs bryResource) BarangayCtx(next http.Handler) http.Handler {
 // ShowBarangays godoc
 //
 //	@Summary		Show list of Barangays
-//	@Description	get Barangays
+//	@Description	get Barangays. With per_page=all the whole list is streamed, without metadata.
 //	@Tags			Barangays
 //	@Accept			json
 //	@Produce		json
-//	@Param			query	query		PaginationParams	false	"Pagination and filter parameters"
-//	@Success		200		{object}	PaginatedBarangay
-//	@Failure		400		{object}	string	"Bad Request"
-//	@Failure		500		{object}	string	"Internal Server Error"
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			query		query		PaginationParams	false	"Pagination and filter parameters, per_page also accepts all"
+//	@Param			urban_rural	query		string				false	"U for urban and R for rural barangays"	Enums(U, R)
+//	@Param			status		query		string				false	"Pob. for the poblacions"				Enums(Pob.)
+//	@Param			filter		query		string				false	"Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, citmun_code, name, urban_rural, status, population_2015, population_2020"
+//	@Param			format		query		string				false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200			{object}	PaginatedBarangay
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
 //	@Router			/barangays [get]
 func (rs bryResource) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	pageParams, ok := ctx.Value(util.PaginateCtx{}).(domain.PaginationParams)
 	if !ok {
-		http.Error(w, "Pagination information not found", http.StatusBadRequest)
+		util.Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter, "Pagination information not found")
 		return
 	}
 
-	data, err := rs.bgyRepo.GetAll(ctx, pageParams)
-	if err != nil {
-		rs.logger.Error("failed to fetch barangays from database", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if pageParams.PerPage == domain.PerPageAll {
+		err := render.Stream(w, r, "barangays", func(fn func(item domain.Barangay) error) error {
+			return rs.bgyRepo.Each(ctx, pageParams, fn)
+		})
+		if err != nil {
+			streamError(w, r, rs.logger, errors.Is(err, render.ErrStreamStarted), err, "failed to stream barangays")
+		}
 		return
 	}
 
-	res, err := json.Marshal(data)
+	data, err := rs.bgyRepo.GetAll(ctx, pageParams)
 	if err != nil {
-		http.Error(w, "Error marshaling response", http.StatusInternalServerError)
+		repositoryError(w, r, rs.logger, err, "failed to fetch barangays from database")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(res)
+	if err := render.List(w, r, data.MetaData, data.Data, "barangays"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
 }
 
 // ShowBarangay godoc
@@ -95,11 +107,15 @@ func (rs bryResource) List(w http.ResponseWriter, r *http.Request) {
 //	@Tags			Barangays
 //	@Accept			json
 //	@Produce		json
-//	@Param			psgc_code	path		string true	"Barangay psgcCode"
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string	true	"Barangay psgcCode"
+//	@Param			format		query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
 //	@Success		200			{object}	domain.Barangay
-//	@Failure		400			{object}	string	"Bad Request"
-//	@Failure		404			{object}	string	"Item Not Found"
-//	@Failure		500			{object}	string	"Internal Server Error"
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
 //	@Router			/barangays/{psgc_code} [get]
 func (rs bryResource) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -107,16 +123,11 @@ func (rs bryResource) Get(w http.ResponseWriter, r *http.Request) {
 	item, ok := ctx.Value(BrgyCtx{}).(domain.Barangay)
 	if !ok {
 
-		http.Error(w, domain.ErrNotFound.Error(), http.StatusNotFound)
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
 		return
 	}
 
-	res, err := json.Marshal(item)
-	if err != nil {
-		http.Error(w, "Error marshaling response", http.StatusInternalServerError)
-		return
+	if err := render.Item(w, r, item, item.PsgcCode); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(res)
 }
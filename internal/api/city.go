@@ -2,10 +2,11 @@ package api
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/render"
 	"github.com/Brix101/psgc-tool/internal/util"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -24,7 +25,7 @@ func (rs cityResource) Routes() chi.Router {
 	r := chi.NewRouter()
 	// r.Use() // some middleware..
 
-	r.With(util.Paginate).Get("/", rs.List) // GET /city - read a list of cities
+	r.With(util.PaginateAll(domain.CityMuniSortFields...), util.Filter(domain.CityMuniAttributes...), util.FilterExpr(domain.CityMuniFilterFields)).Get("/", rs.List) // GET /city - read a list of cities
 
 	r.Route("/{psgc_code}", func(r chi.Router) {
 		r.Use(rs.CitiesCtx) // lets have a cities map, and lets actually load/manipulate
@@ -41,7 +42,7 @@ func (rs cityResource) CitiesCtx(next http.Handler) http.Handler {
 
 		item, err := rs.cityMuniRepo.GetCityById(ctx, psgcCode)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			repositoryError(w, r, rs.logger, err, "failed to fetch city from database")
 			return
 		}
 
@@ -53,39 +54,51 @@ func (rs cityResource) CitiesCtx(next http.Handler) http.Handler {
 // ShowCities godoc
 //
 //	@Summary		Show list of Cities
-//	@Description	get Cities
+//	@Description	get Cities. With per_page=all the whole list is streamed, without metadata.
 //	@Tags			Cities
 //	@Accept			json
 //	@Produce		json
-//	@Param			query	query		PaginationParams	false	"Pagination and filter parameters"
-//	@Success		200		{object}	PaginatedCityMuni
-//	@Failure		400		{object}	string	"Bad Request"
-//	@Failure		500		{object}	string	"Internal Server Error"
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			query			query		PaginationParams	false	"Pagination and filter parameters, per_page also accepts all"
+//	@Param			city_class		query		string				false	"City class, CC for component, HUC for highly urbanized and ICC for independent component cities"	Enums(CC, HUC, ICC)
+//	@Param			income_class	query		string				false	"Income class"																						Enums(1st, 2nd, 3rd, 4th, 5th, 6th, Special)
+//	@Param			status			query		string				false	"Capital for the provincial capitals"																Enums(Capital)
+//	@Param			filter			query		string				false	"Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, prov_code, name, level, city_class, income_class, status, population_2015, population_2020"
+//	@Param			format			query		string				false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200				{object}	PaginatedCityMuni
+//	@Failure		400				{object}	Problem	"Bad Request"
+//	@Failure		500				{object}	Problem	"Internal Server Error"
 //	@Router			/cities [get]
 func (rs cityResource) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	pageParams, ok := ctx.Value(util.PaginateCtx{}).(domain.PaginationParams)
 	if !ok {
-		http.Error(w, "Pagination information not found", http.StatusBadRequest)
+		util.Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter, "Pagination information not found")
 		return
 	}
 
-	data, err := rs.cityMuniRepo.GetAllCity(ctx, pageParams)
-	if err != nil {
-		rs.logger.Error("failed to fetch cities from database", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if pageParams.PerPage == domain.PerPageAll {
+		err := render.Stream(w, r, "cities", func(fn func(item domain.CityMuni) error) error {
+			return rs.cityMuniRepo.EachCity(ctx, pageParams, fn)
+		})
+		if err != nil {
+			streamError(w, r, rs.logger, errors.Is(err, render.ErrStreamStarted), err, "failed to stream cities")
+		}
 		return
 	}
 
-	res, err := json.Marshal(data)
+	data, err := rs.cityMuniRepo.GetAllCity(ctx, pageParams)
 	if err != nil {
-		http.Error(w, "Error marshaling response", http.StatusInternalServerError)
+		repositoryError(w, r, rs.logger, err, "failed to fetch cities from database")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(res)
+	if err := render.List(w, r, data.MetaData, data.Data, "cities"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
 }
 
 // ShowCities godoc
@@ -95,11 +108,15 @@ func (rs cityResource) List(w http.ResponseWriter, r *http.Request) {
 //	@Tags			Cities
 //	@Accept			json
 //	@Produce		json
-//	@Param			psgc_code	path		string true	"City PsgcCode"
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string	true	"City PsgcCode"
+//	@Param			format		query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
 //	@Success		200			{object}	domain.CityMuni
-//	@Failure		400			{object}	string	"Bad Request"
-//	@Failure		404			{object}	string	"Item Not Found"
-//	@Failure		500			{object}	string	"Internal Server Error"
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
 //	@Router			/cities/{psgc_code} [get]
 func (rs cityResource) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -107,16 +124,11 @@ func (rs cityResource) Get(w http.ResponseWriter, r *http.Request) {
 	item, ok := ctx.Value(CityCtx{}).(domain.CityMuni)
 	if !ok {
 
-		http.Error(w, "Item not found", http.StatusNotFound)
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
 		return
 	}
 
-	res, err := json.Marshal(item)
-	if err != nil {
-		http.Error(w, "Error marshaling response", http.StatusInternalServerError)
-		return
+	if err := render.Item(w, r, item, item.PsgcCode); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(res)
 }
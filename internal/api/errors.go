@@ -0,0 +1,85 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/util"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+// repositoryError answers a failed repository call. Errors the client caused
+// get a 4xx, anything else is a failure of the server, e.g. a locked or
+// corrupt database, which is logged and sent as a 500 without its details.
+func repositoryError(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	err error,
+	msg string,
+) {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidCursor):
+		util.ValidationError(w, r, domain.FieldError{
+			Field:      "cursor",
+			Constraint: "cursor",
+			Message:    err.Error() + ".",
+		})
+	default:
+		logger.Error(
+			msg,
+			zap.Error(err),
+			zap.String("path", r.URL.Path),
+			zap.String("request_id", middleware.GetReqID(r.Context())),
+		)
+		util.Error(w, r, http.StatusInternalServerError, domain.CodeInternal, http.StatusText(http.StatusInternalServerError))
+	}
+}
+
+// streamError answers a streamed response that failed. Until the response
+// has started it is a failure like any other. After that the client already
+// has a 200, so the connection is aborted rather than letting a truncated
+// body pass for a complete one. A client that went away needs no answer.
+func streamError(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger *zap.Logger,
+	started bool,
+	err error,
+	msg string,
+) {
+	if r.Context().Err() != nil {
+		return
+	}
+
+	if !started {
+		// The headers of the stream don't apply to the problem
+		w.Header().Del("Content-Encoding")
+		repositoryError(w, r, logger, err, msg)
+		return
+	}
+
+	logger.Error(
+		msg,
+		zap.Error(err),
+		zap.String("path", r.URL.Path),
+		zap.String("request_id", middleware.GetReqID(r.Context())),
+	)
+	panic(http.ErrAbortHandler)
+}
+
+// notFound answers unknown paths. Under /api clients expect JSON, so they get
+// a 404 problem, browsers everywhere else are sent to the Swagger UI.
+func notFound(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/api" || strings.HasPrefix(r.URL.Path, "/api/") {
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, "No endpoint matches "+r.URL.Path+".")
+		return
+	}
+
+	http.Redirect(w, r, "/docs/index.html", http.StatusFound)
+}
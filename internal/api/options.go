@@ -0,0 +1,122 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/render"
+	"github.com/Brix101/psgc-tool/internal/util"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// optionsResource serves the choices of cascading select widgets, e.g. the
+// provinces of a region then the cities/municipalities of a province
+type optionsResource struct {
+	logger      *zap.Logger
+	geoUnitRepo domain.HierarchyRepository
+}
+
+// Routes creates a REST router for the options resource
+func (rs optionsResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.With(util.LongLived).Get("/", rs.List) // GET /options - read the choices of a level below a parent
+
+	return r
+}
+
+// ShowOptions godoc
+//
+//	@Summary		Show the options of a select widget
+//	@Description	get the units of a level right below a parent as value/label pairs sorted by label, e.g. level=Prov&parent=0700000000. The list isn't paginated, a level without a parent is only allowed for regions and provinces.
+//	@Tags			Options
+//	@Accept			json
+//	@Produce		json
+//	@Param			level	query		string	false	"Geographic level of the options"	Enums(Reg, Prov, City, Mun, SGU, Bgy)
+//	@Param			parent	query		string	false	"PsgcCode of the parent"
+//	@Success		200		{array}		domain.Option
+//	@Failure		400		{object}	Problem	"Bad Request"
+//	@Failure		404		{object}	Problem	"Parent Not Found"
+//	@Failure		500		{object}	Problem	"Internal Server Error"
+//	@Router			/options [get]
+func (rs optionsResource) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	level := r.URL.Query().Get("level")
+	parent := r.URL.Query().Get("parent")
+
+	if level != "" && !slices.Contains(domain.Levels, level) {
+		util.ValidationError(w, r, domain.FieldError{
+			Field:      "level",
+			Constraint: "oneof=" + strings.Join(domain.Levels, " "),
+			Message:    "level should be one of " + strings.Join(domain.Levels, ", ") + ".",
+		})
+		return
+	}
+
+	// The units of a level below the provinces are too many for one widget
+	if parent == "" && level != domain.LevelRegion && level != domain.LevelProvince {
+		util.ValidationError(w, r, domain.FieldError{
+			Field:      "parent",
+			Constraint: "required",
+			Message:    "parent is required, except for the Reg and Prov levels.",
+		})
+		return
+	}
+
+	if parent != "" {
+		if err := validateParent(parent); err != nil {
+			util.ValidationError(w, r, *err)
+			return
+		}
+
+		// An unknown parent isn't a parent without children
+		if _, err := rs.geoUnitRepo.GetById(ctx, parent); err != nil {
+			repositoryError(w, r, rs.logger, err, "failed to fetch parent from database")
+			return
+		}
+	}
+
+	params := domain.PaginationParams{
+		Sort:   "name",
+		Order:  domain.OrderAsc,
+		Parent: parent,
+	}
+
+	options := []domain.Option{}
+	err := rs.geoUnitRepo.Each(ctx, level, params, func(item domain.GeoUnit) error {
+		options = append(options, domain.Option{Value: item.PsgcCode, Label: item.Name})
+		return nil
+	})
+	if err != nil {
+		repositoryError(w, r, rs.logger, err, "failed to fetch options from database")
+		return
+	}
+
+	if err := render.Item(w, r, options, "options"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// validateParent checks that parent is a psgc_code, with the constraints
+// Paginate puts on ?parent
+func validateParent(parent string) *domain.FieldError {
+	switch {
+	case strings.Trim(parent, "0123456789") != "":
+		return &domain.FieldError{
+			Field:      "parent",
+			Constraint: "numeric",
+			Message:    "parent should be numeric.",
+		}
+	case len(parent) != 10:
+		return &domain.FieldError{
+			Field:      "parent",
+			Constraint: "len=10",
+			Message:    "parent should be 10 characters long.",
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/util"
+)
+
+func TestOptionsErrors(t *testing.T) {
+	h := newTestAPI(t, Options{})
+
+	tests := []struct {
+		query  string
+		status int
+		// constraint is the constraint of the detail, for a 400
+		constraint string
+	}{
+		{"", http.StatusBadRequest, "required"},
+		{"level=Mun", http.StatusBadRequest, "required"},
+		{"level=Bgy", http.StatusBadRequest, "required"},
+		{"level=Mun&parent=0102800", http.StatusBadRequest, "len=10"},
+		{"level=Mun&parent=01028000001", http.StatusBadRequest, "len=10"},
+		{"level=Mun&parent=01028000ab", http.StatusBadRequest, "numeric"},
+		{"level=Town&parent=0102800000", http.StatusBadRequest, "oneof=Reg Prov City Mun SGU Bgy"},
+		{"level=Mun&parent=9900000000", http.StatusNotFound, ""},
+		{"parent=0102899000", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := get(h, "/api/options?"+tt.query)
+			if rec.Code != tt.status {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body)
+			}
+			if got := rec.Header().Get("Content-Type"); got != util.ProblemContentType {
+				t.Errorf("Content-Type = %q, want %q", got, util.ProblemContentType)
+			}
+
+			var problem domain.Problem
+			if err := json.NewDecoder(rec.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			if tt.constraint == "" {
+				if problem.Code != domain.CodeNotFound {
+					t.Errorf("code = %q, want %q", problem.Code, domain.CodeNotFound)
+				}
+				return
+			}
+			if len(problem.Details) != 1 || problem.Details[0].Constraint != tt.constraint {
+				t.Errorf("details = %+v, want the %s constraint", problem.Details, tt.constraint)
+			}
+		})
+	}
+}
+
+func TestOptionsList(t *testing.T) {
+	h := newTestAPI(t, Options{})
+
+	tests := []struct {
+		query string
+		// first are the first options of the list
+		first []domain.Option
+	}{
+		{"level=Prov&parent=0100000000", []domain.Option{
+			{Value: "0102800000", Label: "Ilocos Norte"},
+			{Value: "0102900000", Label: "Ilocos Sur"},
+			{Value: "0103300000", Label: "La Union"},
+			{Value: "0105500000", Label: "Pangasinan"},
+		}},
+		{"level=Reg", nil},
+		{"level=Prov", nil},
+		// The NCR cities and Pateros, in a region without provinces
+		{"parent=1300000000", nil},
+		{"level=Bgy&parent=0102802000", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			rec := get(h, "/api/options?"+tt.query)
+
+			var options []domain.Option
+			decode(t, rec, &options)
+
+			if len(options) < 2 {
+				t.Fatalf("options = %v, want a list", options)
+			}
+			for i := range tt.first {
+				if options[i] != tt.first[i] {
+					t.Errorf("options[%d] = %+v, want %+v", i, options[i], tt.first[i])
+				}
+			}
+
+			// Labels are sorted by name, whatever their case
+			for i := 1; i < len(options); i++ {
+				if strings.ToLower(options[i-1].Label) > strings.ToLower(options[i].Label) {
+					t.Errorf("%q is listed before %q", options[i-1].Label, options[i].Label)
+				}
+			}
+
+			if got := rec.Header().Get("Cache-Control"); got != util.LongCacheControl {
+				t.Errorf("Cache-Control = %q, want %q", got, util.LongCacheControl)
+			}
+		})
+	}
+}
@@ -2,10 +2,11 @@ package api
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/render"
 	"github.com/Brix101/psgc-tool/internal/util"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -24,7 +25,7 @@ func (rs provResource) Routes() chi.Router {
 	r := chi.NewRouter()
 	// r.Use() // some middleware..
 
-	r.With(util.Paginate).Get("/", rs.List) // GET /provinces - read a list of provinces
+	r.With(util.PaginateAll(domain.ProvinceSortFields...), util.Filter(domain.ProvinceAttributes...), util.FilterExpr(domain.ProvinceFilterFields)).Get("/", rs.List) // GET /provinces - read a list of provinces
 
 	r.Route("/{psgc_code}", func(r chi.Router) {
 		r.Use(rs.ProvinceCtx) // lets have a provinces map, and lets actually load/manipulate
@@ -41,7 +42,7 @@ func (rs provResource) ProvinceCtx(next http.Handler) http.Handler {
 
 		item, err := rs.provRepo.GetById(ctx, psgcCode)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			repositoryError(w, r, rs.logger, err, "failed to fetch province from database")
 			return
 		}
 
@@ -53,39 +54,49 @@ func (rs provResource) ProvinceCtx(next http.Handler) http.Handler {
 // ShowProvinces godoc
 //
 //	@Summary		Show list of Provinces
-//	@Description	get Provinces
+//	@Description	get Provinces. With per_page=all the whole list is streamed, without metadata.
 //	@Tags			Provinces
 //	@Accept			json
 //	@Produce		json
-//	@Param			query	query		PaginationParams	false	"Pagination and filter parameters"
-//	@Success		200		{object}	PaginatedProvince
-//	@Failure		400		{object}	string	"Bad Request"
-//	@Failure		500		{object}	string	"Internal Server Error"
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			query			query		PaginationParams	false	"Pagination and filter parameters, per_page also accepts all"
+//	@Param			income_class	query		string				false	"Income class"	Enums(1st, 2nd, 3rd, 4th, 5th, 6th, Special)
+//	@Param			filter			query		string				false	"Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, reg_code, name, income_class, population_2015, population_2020"
+//	@Param			format			query		string				false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200				{object}	PaginatedProvince
+//	@Failure		400				{object}	Problem	"Bad Request"
+//	@Failure		500				{object}	Problem	"Internal Server Error"
 //	@Router			/provinces [get]
 func (rs provResource) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	pageParams, ok := ctx.Value(util.PaginateCtx{}).(domain.PaginationParams)
 	if !ok {
-		http.Error(w, "Pagination information not found", http.StatusBadRequest)
+		util.Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter, "Pagination information not found")
 		return
 	}
 
-	data, err := rs.provRepo.GetAll(ctx, pageParams)
-	if err != nil {
-		rs.logger.Error("failed to fetch provinces from database", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if pageParams.PerPage == domain.PerPageAll {
+		err := render.Stream(w, r, "provinces", func(fn func(item domain.Province) error) error {
+			return rs.provRepo.Each(ctx, pageParams, fn)
+		})
+		if err != nil {
+			streamError(w, r, rs.logger, errors.Is(err, render.ErrStreamStarted), err, "failed to stream provinces")
+		}
 		return
 	}
 
-	res, err := json.Marshal(data)
+	data, err := rs.provRepo.GetAll(ctx, pageParams)
 	if err != nil {
-		http.Error(w, "Error marshaling response", http.StatusInternalServerError)
+		repositoryError(w, r, rs.logger, err, "failed to fetch provinces from database")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(res)
+	if err := render.List(w, r, data.MetaData, data.Data, "provinces"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
 }
 
 // ShowProvinces godoc
@@ -95,27 +106,26 @@ func (rs provResource) List(w http.ResponseWriter, r *http.Request) {
 //	@Tags			Provinces
 //	@Accept			json
 //	@Produce		json
-//	@Param			psgc_code	path		string true	"Province PsgcCode"
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string	true	"Province PsgcCode"
+//	@Param			format		query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
 //	@Success		200			{object}	domain.Province
-//	@Failure		400			{object}	string	"Bad Request"
-//	@Failure		404			{object}	string	"Item Not Found"
-//	@Failure		500			{object}	string	"Internal Server Error"
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
 //	@Router			/provinces/{psgc_code} [get]
 func (rs provResource) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	item, ok := ctx.Value(ProvCtx{}).(domain.Province)
 	if !ok {
-		http.Error(w, domain.ErrNotFound.Error(), http.StatusNotFound)
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
 		return
 	}
 
-	res, err := json.Marshal(item)
-	if err != nil {
-		http.Error(w, "Error marshaling response", http.StatusInternalServerError)
-		return
+	if err := render.Item(w, r, item, item.PsgcCode); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(res)
 }
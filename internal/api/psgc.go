@@ -0,0 +1,344 @@
+package api
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/cache"
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/export"
+	"github.com/Brix101/psgc-tool/internal/render"
+	"github.com/Brix101/psgc-tool/internal/util"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type (
+	GeoUnitCtx   struct{}
+	psgcResource struct {
+		logger      *zap.Logger
+		geoUnitRepo domain.HierarchyRepository
+		statsRepo   domain.StatsRepository
+		treeWriter  *export.TreeWriter
+	}
+)
+
+// Routes creates a REST router for the psgc resource, which covers every
+// geographic level
+func (rs psgcResource) Routes() chi.Router {
+	r := chi.NewRouter()
+	// r.Use() // some middleware..
+
+	r.Route("/{psgc_code}", func(r chi.Router) {
+		r.Use(rs.GeoUnitCtx) // lets have a geographic units map, and lets actually load/manipulate
+		r.Get("/", rs.Get)   // GET /psgc/{psgc_code} - read a single geographic unit by :id
+
+		// GET /psgc/{psgc_code}/descendants - read the units of a level below :id
+		r.With(util.PaginateAll(domain.GeoUnitSortFields...), util.FilterExpr(domain.GeoUnitFilterFields)).Get("/descendants", rs.Descendants)
+		r.With(cache.NoStore).Get("/tree", rs.Tree) // GET /psgc/{psgc_code}/tree - read :id and the units below it as a nested tree
+		r.Get("/stats", rs.Stats)                   // GET /psgc/{psgc_code}/stats - read the counts and populations of :id
+	})
+
+	return r
+}
+
+func (rs psgcResource) GeoUnitCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		psgcCode := chi.URLParam(r, "psgc_code") // Get the {psgc_code} from the route
+
+		item, err := rs.geoUnitRepo.GetById(ctx, psgcCode)
+		if err != nil {
+			repositoryError(w, r, rs.logger, err, "failed to fetch geographic unit from database")
+			return
+		}
+
+		ctx = context.WithValue(ctx, GeoUnitCtx{}, item)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ShowGeoUnit godoc
+//
+//	@Summary		Show a geographic unit
+//	@Description	get a unit of any level by PsgcCode
+//	@Tags			PSGC
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string	true	"PsgcCode"
+//	@Param			format		query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200			{object}	domain.GeoUnit
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
+//	@Router			/psgc/{psgc_code} [get]
+func (rs psgcResource) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	item, ok := ctx.Value(GeoUnitCtx{}).(domain.GeoUnit)
+	if !ok {
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
+		return
+	}
+
+	if err := render.Item(w, r, item, item.PsgcCode); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// ShowDescendants godoc
+//
+//	@Summary		Show the descendants of a geographic unit
+//	@Description	get every unit of a level under any ancestor, e.g. all barangays of a province. With per_page=all the whole list is streamed, without metadata.
+//	@Tags			PSGC
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string				true	"Ancestor PsgcCode"
+//	@Param			level		query		string				false	"Geographic level of the descendants"	Enums(Prov, City, Mun, SGU, Bgy)
+//	@Param			query		query		PaginationParams	false	"Pagination and filter parameters, per_page also accepts all"
+//	@Param			filter		query		string				false	"Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, name, level, parent_code, population_2015, population_2020"
+//	@Param			format		query		string				false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200			{object}	PaginatedGeoUnit
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
+//	@Router			/psgc/{psgc_code}/descendants [get]
+func (rs psgcResource) Descendants(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	item, pageParams, level, ok := rs.descendantsParams(w, r)
+	if !ok {
+		return
+	}
+
+	if pageParams.PerPage == domain.PerPageAll {
+		err := render.Stream(w, r, item.PsgcCode+"-descendants", func(fn func(item domain.GeoUnit) error) error {
+			return rs.geoUnitRepo.EachDescendant(ctx, item.PsgcCode, level, pageParams, fn)
+		})
+		if err != nil {
+			streamError(w, r, rs.logger, errors.Is(err, render.ErrStreamStarted), err, "failed to stream descendants")
+		}
+		return
+	}
+
+	data, err := rs.geoUnitRepo.Descendants(ctx, item.PsgcCode, level, pageParams)
+	if err != nil {
+		repositoryError(w, r, rs.logger, err, "failed to fetch descendants from database")
+		return
+	}
+
+	if err := render.List(w, r, data.MetaData, data.Data, item.PsgcCode+"-descendants"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// descendantsParams reads the unit, pagination and level of a descendants
+// request, it answers the request itself when they're invalid
+func (rs psgcResource) descendantsParams(
+	w http.ResponseWriter,
+	r *http.Request,
+) (domain.GeoUnit, domain.PaginationParams, string, bool) {
+	ctx := r.Context()
+
+	item, ok := ctx.Value(GeoUnitCtx{}).(domain.GeoUnit)
+	if !ok {
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
+		return item, domain.PaginationParams{}, "", false
+	}
+
+	pageParams, ok := ctx.Value(util.PaginateCtx{}).(domain.PaginationParams)
+	if !ok {
+		util.Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter, "Pagination information not found")
+		return item, pageParams, "", false
+	}
+
+	level := r.URL.Query().Get("level")
+	if level != "" && !slices.Contains(domain.DescendantLevels, level) {
+		util.ValidationError(w, r, domain.FieldError{
+			Field:      "level",
+			Constraint: "oneof=" + strings.Join(domain.DescendantLevels, " "),
+			Message:    "level should be one of " + strings.Join(domain.DescendantLevels, ", ") + ".",
+		})
+		return item, pageParams, "", false
+	}
+
+	return item, pageParams, level, true
+}
+
+// ShowTree godoc
+//
+//	@Summary		Show the tree of a geographic unit
+//	@Description	get a unit and the units below it as one nested document, e.g. region → provinces → cities/municipalities → barangays. The tree is streamed and gzip compressed when the client accepts it.
+//	@Tags			PSGC
+//	@Accept			json
+//	@Produce		json
+//	@Param			psgc_code	path		string	true	"Root PsgcCode"
+//	@Param			depth		query		int		false	"Levels below the root to include"	minimum(0)	maximum(3)	default(3)
+//	@Success		200			{object}	domain.TreeNode
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
+//	@Router			/psgc/{psgc_code}/tree [get]
+func (rs psgcResource) Tree(w http.ResponseWriter, r *http.Request) {
+	item, depth, ok := rs.treeParams(w, r)
+	if !ok {
+		return
+	}
+
+	rs.writeTree(w, r, rs.treeWriter, item.PsgcCode, depth)
+}
+
+// treeParams reads the unit and depth of a tree request, it answers the
+// request itself when they're invalid
+func (rs psgcResource) treeParams(w http.ResponseWriter, r *http.Request) (domain.GeoUnit, int, bool) {
+	item, ok := r.Context().Value(GeoUnitCtx{}).(domain.GeoUnit)
+	if !ok {
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
+		return item, 0, false
+	}
+
+	depth := export.MaxDepth
+	if s := r.URL.Query().Get("depth"); s != "" {
+		var err error
+		depth, err = strconv.Atoi(s)
+		if err != nil || depth < 0 || depth > export.MaxDepth {
+			util.ValidationError(w, r, domain.FieldError{
+				Field:      "depth",
+				Constraint: "min=0 max=3",
+				Message:    "depth should be a number from 0 to 3.",
+			})
+			return item, 0, false
+		}
+	}
+
+	return item, depth, true
+}
+
+// writeTree streams the tree of psgcCode, gzip compressed when the client
+// accepts it
+func (rs psgcResource) writeTree(
+	w http.ResponseWriter,
+	r *http.Request,
+	treeWriter *export.TreeWriter,
+	psgcCode string,
+	depth int,
+) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Add("Vary", "Accept-Encoding")
+
+	sw := util.NewStartedWriter(w)
+	var (
+		out io.Writer = sw
+		gz  *gzip.Writer
+	)
+	if acceptsGzip(r.Header.Get("Accept-Encoding")) {
+		w.Header().Set("Content-Encoding", "gzip")
+
+		gz = gzip.NewWriter(sw)
+		out = gz
+	}
+
+	err := treeWriter.Write(r.Context(), out, psgcCode, depth)
+	// The gzip stream is only closed on success, its trailer would make a
+	// truncated tree look complete
+	if err == nil && gz != nil {
+		err = gz.Close()
+	}
+	if err != nil {
+		streamError(w, r, rs.logger, sw.Started(), err, "failed to write tree")
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, by name
+// or by *, with a q-value above 0
+func acceptsGzip(header string) bool {
+	gzipQ, anyQ := -1.0, -1.0
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(part, ";")
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			k, v, ok := strings.Cut(param, "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(k), "q") {
+				continue
+			}
+
+			var err error
+			if q, err = strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil {
+				q = 0
+			}
+		}
+
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "gzip", "x-gzip":
+			gzipQ = q
+		case "*":
+			anyQ = q
+		}
+	}
+
+	if gzipQ >= 0 {
+		return gzipQ > 0
+	}
+	return anyQ > 0
+}
+
+// ShowStats godoc
+//
+//	@Summary		Show the stats of a geographic unit
+//	@Description	get the number of units of each level below a unit, its 2015 and 2020 populations with the annual growth rate, and the urban/rural split of its barangays
+//	@Tags			PSGC
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string	true	"PsgcCode"
+//	@Param			format		query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200			{object}	domain.Stats
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
+//	@Router			/psgc/{psgc_code}/stats [get]
+func (rs psgcResource) Stats(w http.ResponseWriter, r *http.Request) {
+	stats, ok := rs.stats(w, r)
+	if !ok {
+		return
+	}
+
+	if err := render.Item(w, r, stats, stats.PsgcCode+"-stats"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// stats reads the stats of the unit loaded by GeoUnitCtx, it answers the
+// request itself when that fails
+func (rs psgcResource) stats(w http.ResponseWriter, r *http.Request) (domain.Stats, bool) {
+	ctx := r.Context()
+
+	item, ok := ctx.Value(GeoUnitCtx{}).(domain.GeoUnit)
+	if !ok {
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
+		return domain.Stats{}, false
+	}
+
+	stats, err := rs.statsRepo.Get(ctx, item.PsgcCode)
+	if err != nil {
+		repositoryError(w, r, rs.logger, err, "failed to fetch stats from database")
+		return stats, false
+	}
+
+	return stats, true
+}
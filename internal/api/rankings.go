@@ -0,0 +1,142 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/render"
+	"github.com/Brix101/psgc-tool/internal/util"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultRankingLimit = 10
+	MaxRankingLimit     = 100
+)
+
+// rankingResource serves the top units of a level by a metric, in the same
+// shape in v1 and v2
+type rankingResource struct {
+	logger      *zap.Logger
+	geoUnitRepo domain.HierarchyRepository
+	statsRepo   domain.StatsRepository
+}
+
+// Routes creates a REST router for the rankings resource
+func (rs rankingResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", rs.List) // GET /rankings - read the units of a level ranked by a metric
+
+	return r
+}
+
+// ShowRankings godoc
+//
+//	@Summary		Show the top units of a level
+//	@Description	get the units of a level ranked by a metric, e.g. level=City&metric=population_2020&limit=20 for the 20 most populous cities, or level=Mun&metric=growth_rate&scope=1100000000 for the fastest-growing municipalities of Region XI. Tied units share a rank, limit is the last rank returned so every unit tied at it is included.
+//	@Tags			Rankings
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			level	query		string	true	"Geographic level of the units"	Enums(Reg, Prov, City, Mun, SGU, Bgy)
+//	@Param			metric	query		string	false	"Metric to rank by"				Enums(population_2015, population_2020, population_change, growth_rate)	default(population_2020)
+//	@Param			scope	query		string	false	"PsgcCode of the unit the ranked units are below, the whole country by default"
+//	@Param			order	query		string	false	"desc for the highest first, asc for the lowest first"	Enums(asc, desc)	default(desc)
+//	@Param			limit	query		int		false	"Last rank"												minimum(1)			maximum(100)	default(10)
+//	@Param			format	query		string	false	"Response format, overrides the Accept header"			Enums(json, csv, ndjson, xml)
+//	@Success		200		{object}	domain.RankingResponse
+//	@Failure		400		{object}	Problem	"Bad Request"
+//	@Failure		404		{object}	Problem	"Item Not Found"
+//	@Failure		500		{object}	Problem	"Internal Server Error"
+//	@Router			/rankings [get]
+func (rs rankingResource) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	params, errs := rankingParams(r)
+	if len(errs) > 0 {
+		util.ValidationError(w, r, errs...)
+		return
+	}
+
+	if params.Scope != "" {
+		if _, err := rs.geoUnitRepo.GetById(ctx, params.Scope); err != nil {
+			repositoryError(w, r, rs.logger, err, "failed to fetch geographic unit from database")
+			return
+		}
+	}
+
+	lst, err := rs.statsRepo.Rankings(ctx, params)
+	if err != nil {
+		repositoryError(w, r, rs.logger, err, "failed to fetch rankings from database")
+		return
+	}
+
+	if err := render.All(w, r, lst, "rankings"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// rankingParams reads and validates the query of a ranking
+func rankingParams(r *http.Request) (domain.RankingParams, []domain.FieldError) {
+	query := r.URL.Query()
+	errs := []domain.FieldError{}
+
+	params := domain.RankingParams{
+		Level:  query.Get("level"),
+		Metric: query.Get("metric"),
+		Scope:  query.Get("scope"),
+		Order:  query.Get("order"),
+		Limit:  DefaultRankingLimit,
+	}
+
+	if !slices.Contains(domain.Levels, params.Level) {
+		errs = append(errs, domain.FieldError{
+			Field:      "level",
+			Constraint: "required oneof=" + strings.Join(domain.Levels, " "),
+			Message:    "level is required and should be one of " + strings.Join(domain.Levels, ", ") + ".",
+		})
+	}
+
+	if params.Metric == "" {
+		params.Metric = domain.MetricPopulation2020
+	}
+	if !slices.Contains(domain.RankingMetrics, params.Metric) {
+		errs = append(errs, domain.FieldError{
+			Field:      "metric",
+			Constraint: "oneof=" + strings.Join(domain.RankingMetrics, " "),
+			Message:    "metric should be one of " + strings.Join(domain.RankingMetrics, ", ") + ".",
+		})
+	}
+
+	if params.Order == "" {
+		params.Order = domain.OrderDesc
+	}
+	if params.Order != domain.OrderAsc && params.Order != domain.OrderDesc {
+		errs = append(errs, domain.FieldError{
+			Field:      "order",
+			Constraint: "oneof=asc desc",
+			Message:    "order should be one of asc, desc.",
+		})
+	}
+
+	if s := query.Get("limit"); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > MaxRankingLimit {
+			errs = append(errs, domain.FieldError{
+				Field:      "limit",
+				Constraint: "min=1 max=" + strconv.Itoa(MaxRankingLimit),
+				Message:    "limit should be a number from 1 to " + strconv.Itoa(MaxRankingLimit) + ".",
+			})
+		}
+		params.Limit = limit
+	}
+
+	return params, errs
+}
@@ -2,10 +2,11 @@ package api
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/render"
 	"github.com/Brix101/psgc-tool/internal/util"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
@@ -24,7 +25,7 @@ func (rs regResource) Routes() chi.Router {
 	r := chi.NewRouter()
 	// r.Use() // some middleware..
 
-	r.With(util.Paginate).Get("/", rs.List) // GET /regions - read a list of regions
+	r.With(util.PaginateAll(domain.RegionSortFields...), util.FilterExpr(domain.RegionFilterFields)).Get("/", rs.List) // GET /regions - read a list of regions
 
 	r.Route("/{psgc_code}", func(r chi.Router) {
 		r.Use(rs.RegionCtx) // lets have a regions map, and lets actually load/manipulate
@@ -40,7 +41,7 @@ func (rs regResource) RegionCtx(next http.Handler) http.Handler {
 
 		item, err := rs.regRepo.GetById(ctx, psgcCode)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusNotFound)
+			repositoryError(w, r, rs.logger, err, "failed to fetch region from database")
 			return
 		}
 
@@ -52,39 +53,48 @@ func (rs regResource) RegionCtx(next http.Handler) http.Handler {
 // ShowRegions godoc
 //
 //	@Summary		Show list of Regions
-//	@Description	get Regions
+//	@Description	get Regions. With per_page=all the whole list is streamed, without metadata.
 //	@Tags			Regions
 //	@Accept			json
 //	@Produce		json
-//	@Param			query	query		PaginationParams	false	"Pagination and filter parameters"
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			query	query		PaginationParams	false	"Pagination and filter parameters, per_page also accepts all"
+//	@Param			filter	query		string				false	"Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, name, population_2015, population_2020"
+//	@Param			format	query		string				false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
 //	@Success		200		{object}	PaginatedRegion
-//	@Failure		400		{object}	string	"Bad Request"
-//	@Failure		500		{object}	string	"Internal Server Error"
+//	@Failure		400		{object}	Problem	"Bad Request"
+//	@Failure		500		{object}	Problem	"Internal Server Error"
 //	@Router			/regions [get]
 func (rs regResource) List(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	pageParams, ok := ctx.Value(util.PaginateCtx{}).(domain.PaginationParams)
 	if !ok {
-		http.Error(w, "Pagination information not found", http.StatusBadRequest)
+		util.Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter, "Pagination information not found")
 		return
 	}
 
-	data, err := rs.regRepo.GetAll(ctx, pageParams)
-	if err != nil {
-		rs.logger.Error("failed to fetch regions from database", zap.Error(err))
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if pageParams.PerPage == domain.PerPageAll {
+		err := render.Stream(w, r, "regions", func(fn func(item domain.Region) error) error {
+			return rs.regRepo.Each(ctx, pageParams, fn)
+		})
+		if err != nil {
+			streamError(w, r, rs.logger, errors.Is(err, render.ErrStreamStarted), err, "failed to stream regions")
+		}
 		return
 	}
 
-	res, err := json.Marshal(data)
+	data, err := rs.regRepo.GetAll(ctx, pageParams)
 	if err != nil {
-		http.Error(w, "Error marshaling response", http.StatusInternalServerError)
+		repositoryError(w, r, rs.logger, err, "failed to fetch regions from database")
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(res)
+	if err := render.List(w, r, data.MetaData, data.Data, "regions"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
 }
 
 // ShowRegions godoc
@@ -94,27 +104,26 @@ func (rs regResource) List(w http.ResponseWriter, r *http.Request) {
 //	@Tags			Regions
 //	@Accept			json
 //	@Produce		json
-//	@Param			psgc_code	path		string true	"Region PsgcCode"
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string	true	"Region PsgcCode"
+//	@Param			format		query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
 //	@Success		200			{object}	domain.Region
-//	@Failure		400			{object}	string	"Bad Request"
-//	@Failure		404			{object}	string	"Item Not Found"
-//	@Failure		500			{object}	string	"Internal Server Error"
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
 //	@Router			/regions/{psgc_code} [get]
 func (rs regResource) Get(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	item, ok := ctx.Value(RegCtx{}).(domain.Region)
 	if !ok {
-		http.Error(w, domain.ErrNotFound.Error(), http.StatusNotFound)
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
 		return
 	}
 
-	res, err := json.Marshal(item)
-	if err != nil {
-		http.Error(w, "Error marshaling response", http.StatusInternalServerError)
-		return
+	if err := render.Item(w, r, item, item.PsgcCode); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
 	}
-
-	w.Header().Set("Content-Type", "application/json")
-	w.Write(res)
 }
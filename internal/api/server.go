@@ -8,17 +8,54 @@ import (
 	"time"
 
 	_ "github.com/Brix101/psgc-tool/docs"
+	"github.com/Brix101/psgc-tool/internal/cache"
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/export"
+	"github.com/Brix101/psgc-tool/internal/gql"
+	"github.com/Brix101/psgc-tool/internal/render"
 	"github.com/Brix101/psgc-tool/internal/repository"
+	"github.com/Brix101/psgc-tool/internal/repository/memory"
+	"github.com/Brix101/psgc-tool/internal/rpc"
+	"github.com/Brix101/psgc-tool/internal/suggest"
+	"github.com/Brix101/psgc-tool/internal/util"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/go-chi/cors"
 	"github.com/go-chi/httprate"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// Options configures the API
+type Options struct {
+	// Edition is the date of the data being served
+	Edition time.Time
+	// CacheSize is the memory in bytes the response cache may use, 0
+	// disables it
+	CacheSize int64
+	// Storage is where the regions, provinces, cities/municipalities and
+	// barangays are read from. The unified hierarchy and the stats always stay
+	// in SQLite.
+	Storage string
+	// V1Deprecation is when v2 replaced v1, sent in the Deprecation header
+	// of the v1 responses unless it's zero
+	V1Deprecation time.Time
+	// V1Sunset is when v1, also served without a version under /api, is
+	// removed, sent in the Sunset header unless it's zero
+	V1Sunset time.Time
+}
+
+const (
+	StorageSQLite = "sqlite"
+	// StorageMemory loads the four levels into memory at startup
+	StorageMemory = "memory"
 )
 
 type api struct {
-	logger *zap.Logger
+	logger  *zap.Logger
+	options Options
+	cache   *cache.Cache
 
 	bgyApi      bryResource
 	citiMuniApi citiMuniResource
@@ -26,16 +63,56 @@ type api struct {
 	regApi      regResource
 	cityApi     cityResource
 	munApi      munResource
+	psgcApi     psgcResource
+	statsApi    statsResource
+	rankingApi  rankingResource
+	optionsApi  optionsResource
+	suggestApi  suggestResource
+	// v2Apis are the v2 levels by route
+	v2Apis  map[string]interface{ Routes() chi.Router }
+	graphql *gql.Schema
 }
 
-func NewAPI(_ context.Context, logger *zap.Logger, db *sql.DB) *api {
+func NewAPI(ctx context.Context, logger *zap.Logger, db *sql.DB, options Options) (*api, error) {
 	regRepo := repository.NewDBRegion(db)
 	provRepo := repository.NewDBProvince(db)
 	brgyRepo := repository.NewDBBarangay(db)
 	cityMuniRepo := repository.NewDBCityMuni(db)
+	geoUnitRepo := repository.NewDBHierarchy(db)
+	statsRepo := repository.NewDBStats(db)
+
+	switch options.Storage {
+	case StorageSQLite, "":
+	case StorageMemory:
+		repos, err := memory.Load(ctx, regRepo, provRepo, cityMuniRepo, brgyRepo)
+		if err != nil {
+			return nil, err
+		}
+
+		regRepo, provRepo, cityMuniRepo, brgyRepo = repos.Region, repos.Province, repos.CityMuni, repos.Barangay
+	default:
+		return nil, fmt.Errorf("storage should be one of %s, %s", StorageSQLite, StorageMemory)
+	}
+
+	graphql, err := gql.NewSchema(logger, regRepo, provRepo, cityMuniRepo, brgyRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	suggestIndex, err := suggest.Build(ctx, geoUnitRepo)
+	if err != nil {
+		return nil, err
+	}
+
+	var responseCache *cache.Cache
+	if options.CacheSize > 0 {
+		responseCache = cache.New(options.CacheSize)
+	}
 
 	return &api{
-		logger: logger,
+		logger:  logger,
+		options: options,
+		cache:   responseCache,
 
 		bgyApi: bryResource{
 			logger:  logger,
@@ -61,7 +138,32 @@ func NewAPI(_ context.Context, logger *zap.Logger, db *sql.DB) *api {
 			logger:       logger,
 			cityMuniRepo: cityMuniRepo,
 		},
-	}
+		psgcApi: psgcResource{
+			logger:      logger,
+			geoUnitRepo: geoUnitRepo,
+			statsRepo:   statsRepo,
+			treeWriter:  export.NewTreeWriter(regRepo, provRepo, cityMuniRepo, brgyRepo),
+		},
+		statsApi: statsResource{
+			logger:    logger,
+			statsRepo: statsRepo,
+		},
+		rankingApi: rankingResource{
+			logger:      logger,
+			geoUnitRepo: geoUnitRepo,
+			statsRepo:   statsRepo,
+		},
+		optionsApi: optionsResource{
+			logger:      logger,
+			geoUnitRepo: geoUnitRepo,
+		},
+		suggestApi: suggestResource{
+			logger: logger,
+			index:  suggestIndex,
+		},
+		v2Apis:  v2Resources(logger, regRepo, provRepo, cityMuniRepo, brgyRepo),
+		graphql: graphql,
+	}, nil
 }
 
 func (a *api) Server(port int) *http.Server {
@@ -71,6 +173,12 @@ func (a *api) Server(port int) *http.Server {
 	}
 }
 
+// GRPCServer returns the psgc.v1 gRPC server, over the same repositories
+// as the HTTP API
+func (a *api) GRPCServer() *grpc.Server {
+	return rpc.NewServer(a.logger, a.psgcApi.geoUnitRepo)
+}
+
 func (a *api) Routes() http.Handler {
 	r := chi.NewRouter()
 
@@ -83,7 +191,8 @@ func (a *api) Routes() http.Handler {
 
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"http://*", "https://*"},
-		AllowedMethods:   []string{"GET", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "OPTIONS"},
+		ExposedHeaders:   append([]string{"Content-Disposition", "ETag", "Last-Modified", "X-Edition", "Deprecation", "Sunset", "Link"}, render.MetaDataHeaders...),
 		AllowCredentials: false,
 		MaxAge:           300, // Maximum value not ignored by any of major browsers
 	}))
@@ -93,6 +202,9 @@ func (a *api) Routes() http.Handler {
 		500,           // requests
 		1*time.Minute, // per duration
 		httprate.WithKeyFuncs(httprate.KeyByIP, httprate.KeyByEndpoint),
+		httprate.WithLimitHandler(func(w http.ResponseWriter, r *http.Request) {
+			util.Error(w, r, http.StatusTooManyRequests, domain.CodeTooManyRequests, "Too many requests, try again later.")
+		}),
 	))
 
 	r.Get("/docs/*", httpSwagger.Handler(
@@ -100,18 +212,61 @@ func (a *api) Routes() http.Handler {
 	))
 
 	r.Route("/api", func(r chi.Router) {
-		r.Mount("/barangays", a.bgyApi.Routes())
-		r.Mount("/citi_muni", a.citiMuniApi.Routes())
-		r.Mount("/provinces", a.provApi.Routes())
-		r.Mount("/regions", a.regApi.Routes())
-		r.Mount("/cities", a.cityApi.Routes())
-		r.Mount("/municipalities", a.munApi.Routes())
+		r.Use(util.Caching(a.options.Edition))
+		if a.cache != nil {
+			r.Use(a.cache.Handler)
+		}
+
+		// GraphQL always answers JSON, whatever the Accept header
+		r.Method(http.MethodGet, "/graphql", a.graphql)
+		r.Method(http.MethodPost, "/graphql", a.graphql)
+
+		// Options and suggestions are JSON for form widgets, they're neither
+		// versioned nor negotiated
+		r.Mount("/options", a.optionsApi.Routes())
+		r.Mount("/suggest", a.suggestApi.Routes())
+
+		r.Group(func(r chi.Router) {
+			r.Use(render.Negotiate)
+
+			// v1 is served under /api/v1 and, as it always was, without a
+			// version until its sunset
+			v1 := func(r chi.Router) {
+				r.Use(deprecated(a.options.V1Deprecation, a.options.V1Sunset))
+
+				r.Mount("/barangays", a.bgyApi.Routes())
+				r.Mount("/citi_muni", a.citiMuniApi.Routes())
+				r.Mount("/provinces", a.provApi.Routes())
+				r.Mount("/regions", a.regApi.Routes())
+				r.Mount("/cities", a.cityApi.Routes())
+				r.Mount("/municipalities", a.munApi.Routes())
+				r.Mount("/psgc", a.psgcApi.Routes())
+				r.Mount("/stats", a.statsApi.Routes())
+				r.Mount("/rankings", a.rankingApi.Routes())
+			}
+			r.Route("/v1", v1)
+			r.Group(v1)
+
+			r.Route("/v2", func(r chi.Router) {
+				for route, rs := range a.v2Apis {
+					r.Mount(route, rs.Routes())
+				}
+				r.Mount("/psgc", psgcV2Resource{a.psgcApi}.Routes())
+				r.Mount("/stats", statsV2Resource{a.statsApi}.Routes())
+				// Rankings are a list without metadata, the same in both
+				// versions
+				r.Mount("/rankings", a.rankingApi.Routes())
+			})
+		})
 	})
 
-	// Catch-all route for 404 errors, redirect to Swagger
-	r.NotFound(func(w http.ResponseWriter, r *http.Request) {
-		http.Redirect(w, r, "/docs/index.html", http.StatusFound)
+	r.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		util.Error(w, r, http.StatusMethodNotAllowed, domain.CodeMethodNotAllowed, "The API is read-only, only GET requests are supported, and POST for /api/graphql.")
 	})
 
+	// Catch-all route for 404 errors, JSON under /api and a redirect to
+	// Swagger for browsers
+	r.NotFound(notFound)
+
 	return r
 }
@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"go.uber.org/zap"
+)
+
+// editionFile is the edition tracked in the repository
+const editionFile = "../../db/2023-10-28-data.db"
+
+// newTestAPI serves the tracked edition read-only, it skips the test when
+// the file is missing
+func newTestAPI(t *testing.T, options Options) http.Handler {
+	t.Helper()
+
+	if _, err := os.Stat(editionFile); err != nil {
+		t.Skipf("no data: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+editionFile+"?mode=ro&immutable=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	a, err := NewAPI(context.Background(), zap.NewNop(), db, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.Routes()
+}
+
+func get(h http.Handler, url string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+
+	return rec
+}
+
+// decode reads a JSON response into v, failing on fields v doesn't have
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	dec := json.NewDecoder(rec.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		t.Fatalf("decoding %T: %v", v, err)
+	}
+}
+
+// keys are the sorted fields of a JSON object
+func keys(t *testing.T, data json.RawMessage) []string {
+	t.Helper()
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		t.Fatal(err)
+	}
+
+	lst := []string{}
+	for key := range object {
+		lst = append(lst, key)
+	}
+	slices.Sort(lst)
+
+	return lst
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/render"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type (
+	// statsResource serves the stats of the whole country, the stats of a
+	// unit are under the psgc resource
+	statsResource struct {
+		logger    *zap.Logger
+		statsRepo domain.StatsRepository
+	}
+	// statsV2Resource is the v2 stats resource, in the data envelope
+	statsV2Resource struct {
+		statsResource
+	}
+)
+
+// Routes creates a REST router for the stats resource
+func (rs statsResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", rs.National) // GET /stats - read the counts and populations of the country
+
+	return r
+}
+
+// ShowNationalStats godoc
+//
+//	@Summary		Show the stats of the country
+//	@Description	get the number of units of each level, the 2015 and 2020 populations with the annual growth rate, and the urban/rural split of the barangays of the whole country
+//	@Tags			Stats
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			format	query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200		{object}	domain.Stats
+//	@Failure		400		{object}	Problem	"Bad Request"
+//	@Failure		500		{object}	Problem	"Internal Server Error"
+//	@Router			/stats [get]
+func (rs statsResource) National(w http.ResponseWriter, r *http.Request) {
+	stats, err := rs.statsRepo.National(r.Context())
+	if err != nil {
+		repositoryError(w, r, rs.logger, err, "failed to fetch stats from database")
+		return
+	}
+
+	if err := render.Item(w, r, stats, "stats"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// Routes creates a REST router for the v2 stats resource
+func (rs statsV2Resource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Get("/", rs.National) // GET /v2/stats - read the counts and populations of the country
+
+	return r
+}
+
+// ShowNationalStatsV2 godoc
+//
+//	@Summary		Show the stats of the country
+//	@Description	get the number of units of each level, the 2015 and 2020 populations with the annual growth rate, and the urban/rural split of the barangays of the whole country, in the data envelope
+//	@Tags			v2
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			format	query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200		{object}	StatsResponse
+//	@Failure		400		{object}	Problem	"Bad Request"
+//	@Failure		500		{object}	Problem	"Internal Server Error"
+//	@Router			/v2/stats [get]
+func (rs statsV2Resource) National(w http.ResponseWriter, r *http.Request) {
+	stats, err := rs.statsRepo.National(r.Context())
+	if err != nil {
+		repositoryError(w, r, rs.logger, err, "failed to fetch stats from database")
+		return
+	}
+
+	if err := render.Data(w, r, stats, "stats"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
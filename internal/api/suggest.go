@@ -0,0 +1,100 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/cache"
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/render"
+	"github.com/Brix101/psgc-tool/internal/suggest"
+	"github.com/Brix101/psgc-tool/internal/util"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// maxQueryLength bounds q, names are far shorter
+const maxQueryLength = 100
+
+// suggestResource answers type-ahead queries from the in-memory index
+type suggestResource struct {
+	logger *zap.Logger
+	index  *suggest.Index
+}
+
+// Routes creates a REST router for the suggest resource
+func (rs suggestResource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	// The index answers faster than the response cache would, and every
+	// keystroke is a new query
+	r.With(cache.NoStore).Get("/", rs.List) // GET /suggest - read the units whose name starts with q
+
+	return r
+}
+
+// ShowSuggestions godoc
+//
+//	@Summary		Suggest units by name prefix
+//	@Description	get the units with a word of their name starting with q, e.g. q=taguig&level=City,Bgy. Matching ignores case, diacritics and punctuation. The upper levels come first, then the most populous units.
+//	@Tags			Suggest
+//	@Accept			json
+//	@Produce		json
+//	@Param			q		query		string	true	"Prefix of a word of the name"
+//	@Param			level	query		string	false	"Comma separated geographic levels"
+//	@Param			limit	query		int		false	"Number of suggestions"	minimum(1)	maximum(50)	default(10)
+//	@Success		200		{array}		domain.GeoUnit
+//	@Failure		400		{object}	Problem	"Bad Request"
+//	@Router			/suggest [get]
+func (rs suggestResource) List(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	errs := []domain.FieldError{}
+
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" || len(q) > maxQueryLength {
+		errs = append(errs, domain.FieldError{
+			Field:      "q",
+			Constraint: "required max=" + strconv.Itoa(maxQueryLength),
+			Message:    "q is required and should be at most " + strconv.Itoa(maxQueryLength) + " characters long.",
+		})
+	}
+
+	var levels []string
+	if s := query.Get("level"); s != "" {
+		levels = strings.Split(s, ",")
+		for _, level := range levels {
+			if !slices.Contains(domain.Levels, level) {
+				errs = append(errs, domain.FieldError{
+					Field:      "level",
+					Constraint: "oneof=" + strings.Join(domain.Levels, " "),
+					Message:    "level should be a comma separated list of " + strings.Join(domain.Levels, ", ") + ".",
+				})
+				break
+			}
+		}
+	}
+
+	limit := suggest.DefaultLimit
+	if s := query.Get("limit"); s != "" {
+		var err error
+		limit, err = strconv.Atoi(s)
+		if err != nil || limit < 1 || limit > suggest.MaxLimit {
+			errs = append(errs, domain.FieldError{
+				Field:      "limit",
+				Constraint: "min=1 max=" + strconv.Itoa(suggest.MaxLimit),
+				Message:    "limit should be a number from 1 to " + strconv.Itoa(suggest.MaxLimit) + ".",
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		util.ValidationError(w, r, errs...)
+		return
+	}
+
+	if err := render.Item(w, r, rs.index.Suggest(q, levels, limit), "suggestions"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
@@ -0,0 +1,434 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Brix101/psgc-tool/internal/cache"
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/filter"
+	"github.com/Brix101/psgc-tool/internal/render"
+	"github.com/Brix101/psgc-tool/internal/util"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+// deprecated is a middleware for the v1 routes, it announces their removal
+// with the Deprecation (RFC 9745) and Sunset (RFC 8594) headers and links
+// the v2 API. A zero date leaves its header out.
+func deprecated(deprecation, sunset time.Time) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !deprecation.IsZero() {
+				w.Header().Set("Deprecation", "@"+strconv.FormatInt(deprecation.Unix(), 10))
+			}
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			w.Header().Set("Link", `</api/v2>; rel="successor-version"`)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+type (
+	UnitCtx struct{}
+	// unitResource serves a geographic level in the v2 shape, every item is
+	// a domain.Unit. It reads the same repositories as the v1 resources.
+	unitResource[T interface{ Unit() domain.Unit }] struct {
+		logger *zap.Logger
+		// name is the route of the level, also the file name of downloads
+		name       string
+		sortFields []string
+		attributes []domain.Attribute
+		// filterFields are named like the fields of domain.Unit
+		filterFields filter.Fields
+
+		getAll  func(ctx context.Context, params domain.PaginationParams) (domain.MetaData, []T, error)
+		each    func(ctx context.Context, params domain.PaginationParams, fn func(item T) error) error
+		getById func(ctx context.Context, psgcCode string) (T, error)
+	}
+)
+
+// Routes creates a REST router for a level of the v2 API
+func (rs unitResource[T]) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.With(util.PaginateAll(rs.sortFields...), util.Filter(rs.attributes...), util.FilterExpr(rs.filterFields)).Get("/", rs.List) // GET /v2/{level} - read a list of units
+
+	r.Route("/{psgc_code}", func(r chi.Router) {
+		r.Use(rs.UnitCtx)
+		r.Get("/", rs.Get) // GET /v2/{level}/{psgc_code} - read a single unit by :id
+	})
+
+	return r
+}
+
+func (rs unitResource[T]) UnitCtx(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		psgcCode := chi.URLParam(r, "psgc_code")
+
+		item, err := rs.getById(ctx, psgcCode)
+		if err != nil {
+			repositoryError(w, r, rs.logger, err, "failed to fetch "+rs.name+" from database")
+			return
+		}
+
+		ctx = context.WithValue(ctx, UnitCtx{}, item.Unit())
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// ShowUnits godoc
+//
+//	@Summary		Show list of units of a level
+//	@Description	get the units of a level, every level in the same shape. With per_page=all the whole list is streamed, without metadata.
+//	@Tags			v2
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			query			query		PaginationParams	false	"Pagination and filter parameters, per_page also accepts all"
+//	@Param			city_class		query		string				false	"City class of cities/municipalities and cities"				Enums(CC, HUC, ICC)
+//	@Param			income_class	query		string				false	"Income class of provinces, cities and municipalities"			Enums(1st, 2nd, 3rd, 4th, 5th, 6th, Special)
+//	@Param			urban_rural		query		string				false	"U for urban and R for rural barangays"							Enums(U, R)
+//	@Param			status			query		string				false	"Capital for the provincial capitals, Pob. for the poblacions"	Enums(Capital, Pob.)
+//	@Param			filter			query		string				false	"Filter expression, e.g. level eq 'City' and population_2020 gt 500000, over the fields of Unit the level has"
+//	@Param			format			query		string				false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200				{object}	PaginatedUnit
+//	@Failure		400				{object}	Problem	"Bad Request"
+//	@Failure		500				{object}	Problem	"Internal Server Error"
+//	@Router			/v2/regions [get]
+//	@Router			/v2/provinces [get]
+//	@Router			/v2/cities-municipalities [get]
+//	@Router			/v2/cities [get]
+//	@Router			/v2/municipalities [get]
+//	@Router			/v2/barangays [get]
+func (rs unitResource[T]) List(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	pageParams, ok := ctx.Value(util.PaginateCtx{}).(domain.PaginationParams)
+	if !ok {
+		util.Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter, "Pagination information not found")
+		return
+	}
+
+	if pageParams.PerPage == domain.PerPageAll {
+		err := render.Stream(w, r, rs.name, func(fn func(item domain.Unit) error) error {
+			return rs.each(ctx, pageParams, func(item T) error {
+				return fn(item.Unit())
+			})
+		})
+		if err != nil {
+			streamError(w, r, rs.logger, errors.Is(err, render.ErrStreamStarted), err, "failed to stream "+rs.name)
+		}
+		return
+	}
+
+	metaData, lst, err := rs.getAll(ctx, pageParams)
+	if err != nil {
+		repositoryError(w, r, rs.logger, err, "failed to fetch "+rs.name+" from database")
+		return
+	}
+
+	if err := render.List(w, r, metaData, toUnits(lst), rs.name); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// ShowUnit godoc
+//
+//	@Summary		Show a unit of a level
+//	@Description	get a unit by PsgcCode, in the data envelope
+//	@Tags			v2
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string	true	"PsgcCode"
+//	@Param			format		query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200			{object}	UnitResponse
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
+//	@Router			/v2/regions/{psgc_code} [get]
+//	@Router			/v2/provinces/{psgc_code} [get]
+//	@Router			/v2/cities-municipalities/{psgc_code} [get]
+//	@Router			/v2/cities/{psgc_code} [get]
+//	@Router			/v2/municipalities/{psgc_code} [get]
+//	@Router			/v2/barangays/{psgc_code} [get]
+func (rs unitResource[T]) Get(w http.ResponseWriter, r *http.Request) {
+	renderUnit(w, r, rs.logger)
+}
+
+// renderUnit writes the unit loaded by a Ctx middleware
+func renderUnit(w http.ResponseWriter, r *http.Request, logger *zap.Logger) {
+	item, ok := r.Context().Value(UnitCtx{}).(domain.Unit)
+	if !ok {
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
+		return
+	}
+
+	if err := render.Data(w, r, item, item.PsgcCode); err != nil {
+		logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+func toUnits[T interface{ Unit() domain.Unit }](lst []T) []domain.Unit {
+	units := make([]domain.Unit, len(lst))
+	for i, item := range lst {
+		units[i] = item.Unit()
+	}
+
+	return units
+}
+
+// psgcV2Resource is the v2 psgc resource, over the unified hierarchy
+type psgcV2Resource struct {
+	psgcResource
+}
+
+// Routes creates a REST router for the v2 psgc resource
+func (rs psgcV2Resource) Routes() chi.Router {
+	r := chi.NewRouter()
+
+	r.Route("/{psgc_code}", func(r chi.Router) {
+		r.Use(rs.GeoUnitCtx)
+		r.Get("/", rs.Get) // GET /v2/psgc/{psgc_code} - read a single geographic unit by :id
+
+		// GET /v2/psgc/{psgc_code}/descendants - read the units of a level below :id
+		r.With(util.PaginateAll(domain.GeoUnitSortFields...), util.FilterExpr(domain.GeoUnitFilterFields)).Get("/descendants", rs.Descendants)
+		r.With(cache.NoStore).Get("/tree", rs.Tree) // GET /v2/psgc/{psgc_code}/tree - read :id and the units below it as a nested tree
+		r.Get("/stats", rs.Stats)                   // GET /v2/psgc/{psgc_code}/stats - read the counts and populations of :id
+	})
+
+	return r
+}
+
+// ShowGeoUnitV2 godoc
+//
+//	@Summary		Show a geographic unit
+//	@Description	get a unit of any level by PsgcCode, in the data envelope. Units of the unified hierarchy don't carry the attributes of their level.
+//	@Tags			v2
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string	true	"PsgcCode"
+//	@Param			format		query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200			{object}	UnitResponse
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
+//	@Router			/v2/psgc/{psgc_code} [get]
+func (rs psgcV2Resource) Get(w http.ResponseWriter, r *http.Request) {
+	item, ok := r.Context().Value(GeoUnitCtx{}).(domain.GeoUnit)
+	if !ok {
+		util.Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
+		return
+	}
+
+	if err := render.Data(w, r, item.Unit(), item.PsgcCode); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// ShowDescendantsV2 godoc
+//
+//	@Summary		Show the descendants of a geographic unit
+//	@Description	get every unit of a level under any ancestor, e.g. all barangays of a province. With per_page=all the whole list is streamed, without metadata.
+//	@Tags			v2
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string				true	"Ancestor PsgcCode"
+//	@Param			level		query		string				false	"Geographic level of the descendants"	Enums(Prov, City, Mun, SGU, Bgy)
+//	@Param			query		query		PaginationParams	false	"Pagination and filter parameters, per_page also accepts all"
+//	@Param			filter		query		string				false	"Filter expression, e.g. name startswith 'San' and population_2020 gt 100000, over psgc_code, name, level, parent_code, population_2015, population_2020"
+//	@Param			format		query		string				false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200			{object}	PaginatedUnit
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
+//	@Router			/v2/psgc/{psgc_code}/descendants [get]
+func (rs psgcV2Resource) Descendants(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	item, pageParams, level, ok := rs.descendantsParams(w, r)
+	if !ok {
+		return
+	}
+
+	if pageParams.PerPage == domain.PerPageAll {
+		err := render.Stream(w, r, item.PsgcCode+"-descendants", func(fn func(item domain.Unit) error) error {
+			return rs.geoUnitRepo.EachDescendant(ctx, item.PsgcCode, level, pageParams, func(item domain.GeoUnit) error {
+				return fn(item.Unit())
+			})
+		})
+		if err != nil {
+			streamError(w, r, rs.logger, errors.Is(err, render.ErrStreamStarted), err, "failed to stream descendants")
+		}
+		return
+	}
+
+	data, err := rs.geoUnitRepo.Descendants(ctx, item.PsgcCode, level, pageParams)
+	if err != nil {
+		repositoryError(w, r, rs.logger, err, "failed to fetch descendants from database")
+		return
+	}
+
+	if err := render.List(w, r, data.MetaData, toUnits(data.Data), item.PsgcCode+"-descendants"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// ShowTreeV2 godoc
+//
+//	@Summary		Show the tree of a geographic unit
+//	@Description	get a unit and the units below it as one nested document, every node a Unit with its children. The tree is streamed and gzip compressed when the client accepts it.
+//	@Tags			v2
+//	@Accept			json
+//	@Produce		json
+//	@Param			psgc_code	path		string	true	"Root PsgcCode"
+//	@Param			depth		query		int		false	"Levels below the root to include"	minimum(0)	maximum(3)	default(3)
+//	@Success		200			{object}	domain.UnitTreeNode
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
+//	@Router			/v2/psgc/{psgc_code}/tree [get]
+func (rs psgcV2Resource) Tree(w http.ResponseWriter, r *http.Request) {
+	item, depth, ok := rs.treeParams(w, r)
+	if !ok {
+		return
+	}
+
+	rs.writeTree(w, r, rs.treeWriter.Units(), item.PsgcCode, depth)
+}
+
+// ShowStatsV2 godoc
+//
+//	@Summary		Show the stats of a geographic unit
+//	@Description	get the number of units of each level below a unit, its 2015 and 2020 populations with the annual growth rate, and the urban/rural split of its barangays, in the data envelope
+//	@Tags			v2
+//	@Accept			json
+//	@Produce		json
+//	@Produce		text/csv
+//	@Produce		application/x-ndjson
+//	@Produce		xml
+//	@Param			psgc_code	path		string	true	"PsgcCode"
+//	@Param			format		query		string	false	"Response format, overrides the Accept header"	Enums(json, csv, ndjson, xml)
+//	@Success		200			{object}	StatsResponse
+//	@Failure		400			{object}	Problem	"Bad Request"
+//	@Failure		404			{object}	Problem	"Item Not Found"
+//	@Failure		500			{object}	Problem	"Internal Server Error"
+//	@Router			/v2/psgc/{psgc_code}/stats [get]
+func (rs psgcV2Resource) Stats(w http.ResponseWriter, r *http.Request) {
+	stats, ok := rs.stats(w, r)
+	if !ok {
+		return
+	}
+
+	if err := render.Data(w, r, stats, stats.PsgcCode+"-stats"); err != nil {
+		rs.logger.Error("failed to write response", zap.Error(err))
+	}
+}
+
+// v2Resources creates the resources of the v2 levels by route, over the
+// repositories of the v1 resources
+func v2Resources(
+	logger *zap.Logger,
+	regRepo domain.RegionRepository,
+	provRepo domain.ProvinceRepository,
+	cityMuniRepo domain.CityMuniRepository,
+	bgyRepo domain.BarangayRepository,
+) map[string]interface{ Routes() chi.Router } {
+	return map[string]interface{ Routes() chi.Router }{
+		"/regions": unitResource[domain.Region]{
+			logger:       logger,
+			name:         "regions",
+			sortFields:   domain.RegionSortFields,
+			filterFields: domain.RegionFilterFields,
+			getAll: func(ctx context.Context, params domain.PaginationParams) (domain.MetaData, []domain.Region, error) {
+				data, err := regRepo.GetAll(ctx, params)
+				return data.MetaData, data.Data, err
+			},
+			each:    regRepo.Each,
+			getById: regRepo.GetById,
+		},
+		"/provinces": unitResource[domain.Province]{
+			logger:       logger,
+			name:         "provinces",
+			sortFields:   domain.ProvinceSortFields,
+			filterFields: domain.ProvinceFilterFields.Rename("reg_code", "parent_code"),
+			attributes:   domain.ProvinceAttributes,
+			getAll: func(ctx context.Context, params domain.PaginationParams) (domain.MetaData, []domain.Province, error) {
+				data, err := provRepo.GetAll(ctx, params)
+				return data.MetaData, data.Data, err
+			},
+			each:    provRepo.Each,
+			getById: provRepo.GetById,
+		},
+		"/cities-municipalities": unitResource[domain.CityMuni]{
+			logger:       logger,
+			name:         "cities-municipalities",
+			sortFields:   domain.CityMuniSortFields,
+			filterFields: domain.CityMuniUnitFilterFields,
+			attributes:   domain.CityMuniAttributes,
+			getAll: func(ctx context.Context, params domain.PaginationParams) (domain.MetaData, []domain.CityMuni, error) {
+				data, err := cityMuniRepo.GetAll(ctx, params)
+				return data.MetaData, data.Data, err
+			},
+			each:    cityMuniRepo.Each,
+			getById: cityMuniRepo.GetById,
+		},
+		"/cities": unitResource[domain.CityMuni]{
+			logger:       logger,
+			name:         "cities",
+			sortFields:   domain.CityMuniSortFields,
+			filterFields: domain.CityMuniUnitFilterFields,
+			attributes:   domain.CityMuniAttributes,
+			getAll: func(ctx context.Context, params domain.PaginationParams) (domain.MetaData, []domain.CityMuni, error) {
+				data, err := cityMuniRepo.GetAllCity(ctx, params)
+				return data.MetaData, data.Data, err
+			},
+			each:    cityMuniRepo.EachCity,
+			getById: cityMuniRepo.GetCityById,
+		},
+		"/municipalities": unitResource[domain.CityMuni]{
+			logger:       logger,
+			name:         "municipalities",
+			sortFields:   domain.CityMuniSortFields,
+			filterFields: domain.CityMuniUnitFilterFields,
+			attributes:   domain.MunicipalityAttributes,
+			getAll: func(ctx context.Context, params domain.PaginationParams) (domain.MetaData, []domain.CityMuni, error) {
+				data, err := cityMuniRepo.GetAllMunicipality(ctx, params)
+				return data.MetaData, data.Data, err
+			},
+			each:    cityMuniRepo.EachMunicipality,
+			getById: cityMuniRepo.GetMunicipalityById,
+		},
+		"/barangays": unitResource[domain.Barangay]{
+			logger:       logger,
+			name:         "barangays",
+			sortFields:   domain.BarangaySortFields,
+			filterFields: domain.BarangayFilterFields.Rename("citmun_code", "parent_code"),
+			attributes:   domain.BarangayAttributes,
+			getAll: func(ctx context.Context, params domain.PaginationParams) (domain.MetaData, []domain.Barangay, error) {
+				data, err := bgyRepo.GetAll(ctx, params)
+				return data.MetaData, data.Data, err
+			},
+			each:    bgyRepo.Each,
+			getById: bgyRepo.GetById,
+		},
+	}
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// TestV1Shape checks that v1 still sends each level in its own shape,
+// without the data envelope of v2, under /api/v1 and /api
+func TestV1Shape(t *testing.T) {
+	h := newTestAPI(t, Options{})
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"/regions/0100000000", []string{"name", "population_2015", "population_2020", "psgc_code"}},
+		{"/provinces/0102800000", []string{"income_class", "name", "population_2015", "population_2020", "psgc_code", "regCode"}},
+		{"/citi_muni/0102801000", []string{"city_class", "income_class", "level", "name", "population_2015", "population_2020", "prov_code", "psgc_code", "status"}},
+		{"/barangays/0102801001", []string{"city_muni_code", "name", "population_2015", "population_2020", "psgc_code", "status", "urban_rural"}},
+	}
+
+	for _, tt := range tests {
+		for _, prefix := range []string{"/api/v1", "/api"} {
+			t.Run(prefix+tt.path, func(t *testing.T) {
+				var item json.RawMessage
+				decode(t, get(h, prefix+tt.path), &item)
+
+				if got := keys(t, item); !slices.Equal(got, tt.want) {
+					t.Errorf("fields = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+
+	t.Run("list", func(t *testing.T) {
+		var page domain.PaginatedProvince
+		decode(t, get(h, "/api/v1/provinces?parent=0100000000"), &page)
+
+		if len(page.Data) != 4 || page.Data[0].RegCode != "0100000000" || page.MetaData.TotalItems != 4 {
+			t.Errorf("provinces = %+v, want the 4 provinces of Region I", page)
+		}
+	})
+}
+
+func TestV1Deprecation(t *testing.T) {
+	deprecation := time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC)
+	h := newTestAPI(t, Options{V1Deprecation: deprecation, V1Sunset: sunset})
+
+	for _, path := range []string{"/api/v1/regions", "/api/regions/0100000000", "/api/v1/psgc/0100000000", "/api/provinces/9900000000"} {
+		t.Run(path, func(t *testing.T) {
+			header := get(h, path).Header()
+
+			if got, want := header.Get("Deprecation"), "@1792368000"; got != want {
+				t.Errorf("Deprecation = %q, want %q", got, want)
+			}
+			if got, want := header.Get("Sunset"), "Fri, 30 Apr 2027 00:00:00 GMT"; got != want {
+				t.Errorf("Sunset = %q, want %q", got, want)
+			}
+			if got, want := header.Get("Link"), `</api/v2>; rel="successor-version"`; got != want {
+				t.Errorf("Link = %q, want %q", got, want)
+			}
+		})
+	}
+
+	// v2 and the unversioned routes aren't deprecated
+	for _, path := range []string{"/api/v2/regions", "/api/v2/psgc/0100000000", "/api/options"} {
+		t.Run(path, func(t *testing.T) {
+			header := get(h, path).Header()
+			for _, name := range []string{"Deprecation", "Sunset", "Link"} {
+				if value := header.Get(name); value != "" {
+					t.Errorf("%s = %q, want none", name, value)
+				}
+			}
+		})
+	}
+
+	t.Run("dates not set", func(t *testing.T) {
+		header := get(newTestAPI(t, Options{}), "/api/v1/regions").Header()
+
+		if header.Get("Deprecation") != "" || header.Get("Sunset") != "" {
+			t.Errorf("Deprecation = %q, Sunset = %q, want none", header.Get("Deprecation"), header.Get("Sunset"))
+		}
+		if header.Get("Link") == "" {
+			t.Error("no Link to v2")
+		}
+	})
+}
+
+func TestV2Unit(t *testing.T) {
+	h := newTestAPI(t, Options{})
+
+	tests := []struct {
+		path string
+		want domain.Unit
+	}{
+		{"/api/v2/regions/0100000000", domain.Unit{PsgcCode: "0100000000", Name: "Region I (Ilocos Region)", Level: domain.LevelRegion, Population2015: 5026128, Population2020: 5301139}},
+		{"/api/v2/cities-municipalities/0102801000", domain.Unit{PsgcCode: "0102801000", Name: "Adams", Level: domain.LevelMunicipality, ParentCode: "0102800000"}},
+		{"/api/v2/cities/1380100000", domain.Unit{PsgcCode: "1380100000", Name: "City of Caloocan", Level: domain.LevelCity, ParentCode: "1300000000"}},
+		{"/api/v2/barangays/0102801001", domain.Unit{PsgcCode: "0102801001", Level: domain.LevelBarangay, ParentCode: "0102801000"}},
+		{"/api/v2/psgc/0102800000", domain.Unit{PsgcCode: "0102800000", Name: "Ilocos Norte", Level: domain.LevelProvince, ParentCode: "0100000000"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			var res domain.UnitResponse
+			decode(t, get(h, tt.path), &res)
+
+			// Only the fields the test names are compared
+			got := res.Data
+			if tt.want.Name == "" {
+				got.Name = ""
+			}
+			if tt.want.Population2020 == 0 {
+				got.Population2015, got.Population2020 = 0, 0
+			}
+			got.IncomeClass, got.CityClass, got.UrbanRural, got.Status = "", "", "", ""
+
+			if got != tt.want {
+				t.Errorf("data = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("list", func(t *testing.T) {
+		var page domain.PaginatedUnit
+		decode(t, get(h, "/api/v2/cities-municipalities?parent=1300000000&per_page=5"), &page)
+
+		if len(page.Data) != 5 || page.MetaData.TotalItems < 5 {
+			t.Fatalf("page = %+v, want 5 units", page)
+		}
+		for _, unit := range page.Data {
+			if unit.ParentCode != "1300000000" || unit.Level == "" {
+				t.Errorf("unit = %+v, want a unit of NCR", unit)
+			}
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		if rec := get(h, "/api/v2/provinces/0100000000"); rec.Code != http.StatusNotFound {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusNotFound)
+		}
+	})
+}
@@ -0,0 +1,299 @@
+// Package cache keeps encoded API responses in memory, in front of the
+// resource handlers
+package cache
+
+import (
+	"context"
+	"expvar"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+)
+
+type ctxKey struct{}
+
+// Cache is a size-bounded LRU of GET responses. Concurrent requests for a
+// response that isn't cached yet are coalesced, the first one runs the
+// handler and the others are sent its response.
+type Cache struct {
+	lru *lru
+	// maxEntry is the largest response that is cached, larger ones, like
+	// streamed lists, are only passed through
+	maxEntry int64
+
+	mu       sync.Mutex
+	inflight map[string]*call
+
+	hits      atomic.Int64
+	misses    atomic.Int64
+	coalesced atomic.Int64
+	evictions atomic.Int64
+}
+
+// call is a handler run that identical requests wait on
+type call struct {
+	once     sync.Once
+	done     chan struct{}
+	response *Response // nil if the response can't be shared
+}
+
+// current is the cache reported in the metrics
+var current atomic.Pointer[Cache]
+
+func init() {
+	// Served on /debug/vars next to the pprof endpoints
+	expvar.Publish("response_cache", expvar.Func(func() interface{} {
+		if c := current.Load(); c != nil {
+			return c.Stats()
+		}
+		return nil
+	}))
+}
+
+// New creates a cache holding up to maxBytes of responses
+func New(maxBytes int64) *Cache {
+	c := &Cache{
+		maxEntry: maxBytes / 8,
+		inflight: map[string]*call{},
+	}
+	c.lru = newLRU(maxBytes, func() { c.evictions.Add(1) })
+
+	current.Store(c)
+	return c
+}
+
+// Stats are the cache metrics
+type Stats struct {
+	Hits      int64 `json:"hits"`
+	Misses    int64 `json:"misses"`
+	Coalesced int64 `json:"coalesced"`
+	Evictions int64 `json:"evictions"`
+	Entries   int   `json:"entries"`
+	Bytes     int64 `json:"bytes"`
+	// HitRatio is the share of requests that didn't run a handler, cache
+	// hits and coalesced requests
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+func (c *Cache) Stats() Stats {
+	stats := Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Coalesced: c.coalesced.Load(),
+		Evictions: c.evictions.Load(),
+	}
+	stats.Entries, stats.Bytes = c.lru.stats()
+
+	if total := stats.Hits + stats.Misses + stats.Coalesced; total > 0 {
+		stats.HitRatio = float64(stats.Hits+stats.Coalesced) / float64(total)
+	}
+
+	return stats
+}
+
+// Handler is the cache middleware. Only complete 200 responses are stored,
+// a route opts out with NoStore and a handler with Skip.
+func (c *Cache) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := Key(r)
+
+		if response, ok := c.lru.get(key); ok {
+			c.hits.Add(1)
+			write(w, response, "HIT")
+			return
+		}
+
+		c.mu.Lock()
+		if cl, ok := c.inflight[key]; ok {
+			c.mu.Unlock()
+
+			select {
+			case <-cl.done:
+			case <-r.Context().Done():
+				return
+			}
+
+			if cl.response != nil {
+				c.coalesced.Add(1)
+				write(w, cl.response, "HIT")
+				return
+			}
+
+			// The first request's response wasn't shareable, this one
+			// runs the handler too
+			c.misses.Add(1)
+			w.Header().Set("X-Cache", "MISS")
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cl := &call{done: make(chan struct{})}
+		c.inflight[key] = cl
+		c.mu.Unlock()
+
+		c.misses.Add(1)
+		w.Header().Set("X-Cache", "MISS")
+
+		rec := &recorder{
+			ResponseWriter: w,
+			maxSize:        c.maxEntry,
+			status:         http.StatusOK,
+			release:        func() { c.release(key, cl, nil) },
+		}
+		// complete stays false when the handler panics, e.g. to abort a
+		// response that broke off
+		complete := false
+		defer func() {
+			if rec.abandoned || !rec.wroteHeader || !complete {
+				c.release(key, cl, nil)
+				return
+			}
+
+			response := &Response{Status: rec.status, Header: rec.header, Body: rec.body}
+			c.lru.add(key, response)
+			c.release(key, cl, response)
+		}()
+
+		ctx := context.WithValue(r.Context(), ctxKey{}, rec)
+		next.ServeHTTP(rec, r.WithContext(ctx))
+		complete = true
+	})
+}
+
+// release wakes the requests waiting on cl, the first call wins
+func (c *Cache) release(key string, cl *call, response *Response) {
+	cl.once.Do(func() {
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+
+		cl.response = response
+		close(cl.done)
+	})
+}
+
+// NoStore is a middleware for routes whose responses aren't cached, e.g.
+// large documents that are streamed
+func NoStore(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Skip(r)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Skip keeps the response of r out of the cache, requests waiting on it run
+// their own handler instead
+func Skip(r *http.Request) {
+	if rec, ok := r.Context().Value(ctxKey{}).(*recorder); ok {
+		rec.abandon()
+	}
+}
+
+// Key identifies a response by the request's path, its normalized query,
+// sorted and without empty parameters, and the headers the API varies on
+func Key(r *http.Request) string {
+	query := url.Values{}
+	for k, vs := range r.URL.Query() {
+		for _, v := range vs {
+			if v != "" {
+				query.Add(k, v)
+			}
+		}
+	}
+
+	return r.URL.Path + "?" + query.Encode() +
+		"\x00" + r.Header.Get("Accept") +
+		"\x00" + r.Header.Get("Accept-Encoding")
+}
+
+// requestHeaders are set by the middlewares in front of the cache for each
+// request, a stored response doesn't carry them. The ETag hashes the raw
+// query, which requests sharing an entry needn't have in common.
+var requestHeaders = []string{
+	"X-Cache",
+	"ETag",
+	"Last-Modified",
+	"X-Ratelimit-Limit",
+	"X-Ratelimit-Remaining",
+	"X-Ratelimit-Reset",
+}
+
+func write(w http.ResponseWriter, response *Response, status string) {
+	for k, vs := range response.Header {
+		w.Header()[k] = vs
+	}
+	w.Header().Set("X-Cache", status)
+
+	w.WriteHeader(response.Status)
+	w.Write(response.Body)
+}
+
+// recorder passes a response through to the client while keeping a copy of
+// it, until it turns out not to be cacheable
+type recorder struct {
+	http.ResponseWriter
+	maxSize int64
+	release func()
+
+	wroteHeader bool
+	abandoned   bool
+	status      int
+	header      http.Header
+	body        []byte
+}
+
+func (rec *recorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.wroteHeader = true
+		rec.status = status
+		rec.header = rec.ResponseWriter.Header().Clone()
+		for _, k := range requestHeaders {
+			rec.header.Del(k)
+		}
+
+		if status != http.StatusOK {
+			rec.abandon()
+		}
+	}
+
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *recorder) Write(b []byte) (int, error) {
+	if !rec.wroteHeader {
+		rec.WriteHeader(http.StatusOK)
+	}
+
+	if !rec.abandoned {
+		if int64(len(rec.body)+len(b)) > rec.maxSize {
+			rec.abandon()
+		} else {
+			rec.body = append(rec.body, b...)
+		}
+	}
+
+	return rec.ResponseWriter.Write(b)
+}
+
+// abandon stops recording and lets the waiting requests go
+func (rec *recorder) abandon() {
+	if rec.abandoned {
+		return
+	}
+
+	rec.abandoned = true
+	rec.body = nil
+	rec.release()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer to flush
+// streamed responses
+func (rec *recorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
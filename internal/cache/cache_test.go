@@ -0,0 +1,235 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	request := func(target, accept string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, target, nil)
+		if accept != "" {
+			r.Header.Set("Accept", accept)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name string
+		a, b *http.Request
+		same bool
+	}{
+		{
+			name: "parameter order",
+			a:    request("/api/provinces?page=2&per_page=10", ""),
+			b:    request("/api/provinces?per_page=10&page=2", ""),
+			same: true,
+		},
+		{
+			name: "empty parameters",
+			a:    request("/api/provinces?per_page=2&keyword=", ""),
+			b:    request("/api/provinces?per_page=2", ""),
+			same: true,
+		},
+		{
+			name: "parameter values",
+			a:    request("/api/provinces?per_page=2", ""),
+			b:    request("/api/provinces?per_page=3", ""),
+		},
+		{
+			name: "paths",
+			a:    request("/api/provinces", ""),
+			b:    request("/api/regions", ""),
+		},
+		{
+			name: "query in the path",
+			a:    request("/api/provinces?x=1", ""),
+			b:    request("/api/provinces%3Fx=1", ""),
+		},
+		{
+			name: "Accept",
+			a:    request("/api/provinces", "text/csv"),
+			b:    request("/api/provinces", "application/json"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := Key(tt.a), Key(tt.b)
+			if (a == b) != tt.same {
+				t.Errorf("Key(a) = %q, Key(b) = %q, want same = %v", a, b, tt.same)
+			}
+		})
+	}
+
+	gzip := request("/api/provinces", "")
+	gzip.Header.Set("Accept-Encoding", "gzip")
+	if Key(gzip) == Key(request("/api/provinces", "")) {
+		t.Error("Accept-Encoding isn't part of the key")
+	}
+}
+
+// counting returns a handler running fn then writing the request's path, and
+// the number of times it ran
+func counting(fn func(w http.ResponseWriter, r *http.Request)) (http.Handler, *atomic.Int64) {
+	runs := &atomic.Int64{}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		runs.Add(1)
+		if fn != nil {
+			fn(w, r)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"path":%q}`, r.URL.Path)
+	}), runs
+}
+
+func get(h http.Handler, target string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func TestHandlerReplaysRequestHeaders(t *testing.T) {
+	next, runs := counting(nil)
+
+	// Stands in for the caching and rate limiting middlewares, which set
+	// their headers for each request before the cache
+	cached := New(1 << 20).Handler(next)
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"`+r.URL.RawQuery+`"`)
+		w.Header().Set("X-Ratelimit-Remaining", r.URL.RawQuery)
+		cached.ServeHTTP(w, r)
+	})
+
+	first := get(h, "/api/provinces?per_page=2&keyword=")
+	second := get(h, "/api/provinces?per_page=2")
+
+	if runs.Load() != 1 || second.Header().Get("X-Cache") != "HIT" {
+		t.Fatalf("handler ran %d times, second request %s, want a HIT", runs.Load(), second.Header().Get("X-Cache"))
+	}
+	if second.Body.String() != first.Body.String() {
+		t.Errorf("HIT body = %s, want %s", second.Body, first.Body)
+	}
+	if etag := second.Header().Get("ETag"); etag != `"per_page=2"` {
+		t.Errorf("HIT ETag = %s, want the request's own", etag)
+	}
+	if remaining := second.Header().Get("X-Ratelimit-Remaining"); remaining != "per_page=2" {
+		t.Errorf("HIT X-Ratelimit-Remaining = %s, want the request's own", remaining)
+	}
+	if got := second.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("HIT Content-Type = %q, want the stored one", got)
+	}
+}
+
+func TestHandlerCoalesces(t *testing.T) {
+	const n = 10
+
+	tests := []struct {
+		name string
+		skip bool
+		// wantRuns is how many times the handler runs for n requests
+		wantRuns int64
+	}{
+		{"shared response", false, 1},
+		{"skipped response", true, n},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started, unblock := make(chan struct{}), make(chan struct{})
+			var once sync.Once
+
+			next, runs := counting(func(w http.ResponseWriter, r *http.Request) {
+				if tt.skip {
+					Skip(r)
+				}
+				once.Do(func() {
+					close(started)
+					<-unblock
+				})
+			})
+			h := New(1 << 20).Handler(next)
+
+			bodies := make([]string, n)
+			var wg sync.WaitGroup
+			wg.Add(n)
+			go func() {
+				defer wg.Done()
+				bodies[0] = get(h, "/api/regions").Body.String()
+			}()
+
+			// The others arrive while the first is running, or after it
+			// finished, either way they don't run the handler again
+			<-started
+			for i := 1; i < n; i++ {
+				go func(i int) {
+					defer wg.Done()
+					bodies[i] = get(h, "/api/regions").Body.String()
+				}(i)
+			}
+			close(unblock)
+			wg.Wait()
+
+			if got := runs.Load(); got != tt.wantRuns {
+				t.Errorf("handler ran %d times, want %d", got, tt.wantRuns)
+			}
+			for i, body := range bodies {
+				if body != bodies[0] {
+					t.Errorf("response %d = %q, want %q", i, body, bodies[0])
+				}
+			}
+		})
+	}
+}
+
+func TestHandlerEvicts(t *testing.T) {
+	// Responses are about 650 bytes, the cache holds a dozen of them and the
+	// largest entry is 1,000 bytes
+	c := New(8000)
+	next, runs := counting(func(w http.ResponseWriter, r *http.Request) {
+		size := 600
+		if r.URL.Query().Has("large") {
+			size = 2000
+		}
+		w.Write([]byte(strings.Repeat(" ", size)))
+	})
+	h := c.Handler(next)
+
+	for i := 0; i < 20; i++ {
+		get(h, fmt.Sprintf("/api/regions/%02d", i))
+		// Keeps the first one recently used
+		get(h, "/api/regions/00")
+	}
+
+	stats := c.Stats()
+	if stats.Bytes > 8000 || stats.Evictions == 0 {
+		t.Errorf("stats = %+v, want evictions and at most 8000 bytes", stats)
+	}
+
+	tests := []struct {
+		target string
+		want   string
+	}{
+		{"/api/regions/00", "HIT"},
+		{"/api/regions/19", "HIT"},
+		{"/api/regions/01", "MISS"},
+	}
+	for _, tt := range tests {
+		if got := get(h, tt.target).Header().Get("X-Cache"); got != tt.want {
+			t.Errorf("%s: X-Cache = %s, want %s", tt.target, got, tt.want)
+		}
+	}
+
+	// Too large to keep
+	before := runs.Load()
+	get(h, "/api/regions?large")
+	get(h, "/api/regions?large")
+	if got := runs.Load() - before; got != 2 {
+		t.Errorf("handler ran %d times for a response over the largest entry, want 2", got)
+	}
+}
@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"container/list"
+	"net/http"
+	"sync"
+)
+
+// Response is an encoded response as it was sent
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// size is roughly the memory a response holds
+func (r *Response) size() int64 {
+	size := int64(len(r.Body))
+	for k, vs := range r.Header {
+		size += int64(len(k))
+		for _, v := range vs {
+			size += int64(len(v))
+		}
+	}
+
+	return size
+}
+
+type entry struct {
+	key      string
+	response *Response
+}
+
+// lru holds responses up to maxBytes, evicting the least recently used
+type lru struct {
+	mu       sync.Mutex
+	maxBytes int64
+	bytes    int64
+	ll       *list.List
+	items    map[string]*list.Element
+
+	onEvict func()
+}
+
+func newLRU(maxBytes int64, onEvict func()) *lru {
+	return &lru{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    map[string]*list.Element{},
+		onEvict:  onEvict,
+	}
+}
+
+func (c *lru) get(key string) (*Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	c.ll.MoveToFront(el)
+	return el.Value.(*entry).response, true
+}
+
+func (c *lru) add(key string, response *Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.bytes -= el.Value.(*entry).response.size()
+		el.Value.(*entry).response = response
+		c.bytes += response.size()
+		c.ll.MoveToFront(el)
+	} else {
+		c.items[key] = c.ll.PushFront(&entry{key: key, response: response})
+		c.bytes += response.size()
+	}
+
+	for c.bytes > c.maxBytes && c.ll.Len() > 0 {
+		el := c.ll.Back()
+		e := el.Value.(*entry)
+
+		c.ll.Remove(el)
+		delete(c.items, e.key)
+		c.bytes -= e.response.size()
+		c.onEvict()
+	}
+}
+
+// stats returns the number of entries and the bytes they hold
+func (c *lru) stats() (int, int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.ll.Len(), c.bytes
+}
@@ -2,6 +2,8 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"net"
 	"os"
 	"strconv"
 	"time"
@@ -13,7 +15,15 @@ import (
 )
 
 func APICmd(ctx context.Context) *cobra.Command {
-	var port int
+	var (
+		port      int
+		grpcPort  int
+		cacheSize int64
+		storage   string
+		// v1Deprecation and v1Sunset are dates in the EditionLayout
+		v1Deprecation string
+		v1Sunset      string
+	)
 	cmd := &cobra.Command{
 		Use:   "api",
 		Args:  cobra.ExactArgs(0),
@@ -32,9 +42,44 @@ func APICmd(ctx context.Context) *cobra.Command {
 			}
 			defer db.Close()
 
-			api := api.NewAPI(ctx, logger, db)
+			edition, err := util.Edition()
+			if err != nil {
+				return err
+			}
+
+			options := api.Options{
+				Edition:   edition,
+				CacheSize: cacheSize << 20,
+				Storage:   storage,
+			}
+			if options.V1Deprecation, err = parseDate("v1-deprecation", v1Deprecation); err != nil {
+				return err
+			}
+			if options.V1Sunset, err = parseDate("v1-sunset", v1Sunset); err != nil {
+				return err
+			}
+
+			api, err := api.NewAPI(ctx, logger, db, options)
+			if err != nil {
+				return err
+			}
 			server := api.Server(port)
 
+			// The gRPC service listens on its own port, it is bound before
+			// the HTTP server starts so a port in use is a startup error
+			if grpcPort > 0 {
+				listener, err := net.Listen("tcp", fmt.Sprintf(":%d", grpcPort))
+				if err != nil {
+					return fmt.Errorf("gRPC service: %w", err)
+				}
+
+				grpcServer := api.GRPCServer()
+				go func() { _ = grpcServer.Serve(listener) }()
+				defer grpcServer.GracefulStop()
+
+				logger.Info("🚀🚀🚀 gRPC server at port:", zap.Int("port", grpcPort))
+			}
+
 			// Graceful shutdown with a 30-second timeout
 			shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 30*time.Second)
 			defer shutdownCancel()
@@ -57,6 +102,25 @@ func APICmd(ctx context.Context) *cobra.Command {
 	}
 
 	cmd.Flags().IntVarP(&port, "port", "P", 5000, "Port number")
+	cmd.Flags().IntVar(&grpcPort, "grpc-port", 0, "Port number of the gRPC service, 0 disables it")
+	cmd.Flags().StringVar(&storage, "storage", api.StorageSQLite, "Storage of the geographic levels, sqlite or memory")
+	cmd.Flags().Int64Var(&cacheSize, "cache-size", 64, "Response cache size in MB, 0 disables it")
+	cmd.Flags().StringVar(&v1Deprecation, "v1-deprecation", "", "Date v1 was deprecated, YYYY-MM-DD, sent in the Deprecation header")
+	cmd.Flags().StringVar(&v1Sunset, "v1-sunset", "", "Date v1 is removed, YYYY-MM-DD, sent in the Sunset header")
 
 	return cmd
 }
+
+// parseDate parses the date of a flag, an empty date is the zero time
+func parseDate(flag, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(util.EditionLayout, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--%s should be a date like 2006-01-02: %w", flag, err)
+	}
+
+	return date, nil
+}
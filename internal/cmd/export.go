@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"io"
+	"os"
+
+	"github.com/Brix101/psgc-tool/internal/export"
+	"github.com/Brix101/psgc-tool/internal/repository"
+	"github.com/Brix101/psgc-tool/internal/util"
+	"github.com/spf13/cobra"
+)
+
+func ExportCmd(ctx context.Context) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the PSGC data.",
+	}
+
+	cmd.AddCommand(exportTreeCmd(ctx))
+
+	return cmd
+}
+
+func exportTreeCmd(ctx context.Context) *cobra.Command {
+	var (
+		depth    int
+		output   string
+		compress bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tree [psgc_code]",
+		Args:  cobra.MaximumNArgs(1),
+		Short: "Export a unit and the units below it as a nested JSON tree.",
+		Long:  "Export a unit and the units below it as a nested JSON tree, or every region when no PSGC code is given.",
+		RunE: func(_ *cobra.Command, args []string) error {
+			db, err := util.NewSQLitePool(ctx)
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			var out io.Writer = os.Stdout
+			var f *os.File
+			if output != "" {
+				f, err = os.Create(output)
+				if err != nil {
+					return err
+				}
+				out = f
+			}
+
+			var gz *gzip.Writer
+			if compress {
+				gz = gzip.NewWriter(out)
+				out = gz
+			}
+
+			treeWriter := export.NewTreeWriter(
+				repository.NewDBRegion(db),
+				repository.NewDBProvince(db),
+				repository.NewDBCityMuni(db),
+				repository.NewDBBarangay(db),
+			)
+
+			if len(args) == 0 {
+				err = treeWriter.WriteAll(ctx, out, depth)
+			} else {
+				err = treeWriter.Write(ctx, out, args[0], depth)
+			}
+			// The gzip stream is only closed on success, its trailer would
+			// make a truncated tree look complete
+			if err == nil && gz != nil {
+				err = gz.Close()
+			}
+			if f == nil {
+				return err
+			}
+
+			// The file is closed before it is removed, and a failed close
+			// fails an export that was written
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				// Don't leave a partial export behind
+				if removeErr := os.Remove(output); removeErr != nil {
+					err = errors.Join(err, removeErr)
+				}
+			}
+
+			return err
+		},
+	}
+
+	cmd.Flags().IntVarP(&depth, "depth", "d", export.MaxDepth, "Levels below the root to include")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Output file, defaults to stdout")
+	cmd.Flags().BoolVarP(&compress, "gzip", "z", false, "Gzip the output")
+
+	return cmd
+}
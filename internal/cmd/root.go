@@ -56,6 +56,7 @@ func Execute(ctx context.Context) int {
 
 	rootCmd.AddCommand(APICmd(ctx))
 	rootCmd.AddCommand(GeneratorCmd(ctx))
+	rootCmd.AddCommand(ExportCmd(ctx))
 
 	go func() {
 		_ = http.ListenAndServe("localhost:6060", nil)
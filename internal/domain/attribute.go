@@ -0,0 +1,76 @@
+package domain
+
+import "strings"
+
+// Attribute is a column of the masterlist a list can be filtered on, with
+// the values it is filtered by. The query parameter is named after the
+// column.
+type Attribute struct {
+	Name   string
+	Values []string
+}
+
+var (
+	// CityClass is CC for component cities, HUC for highly urbanized cities
+	// and ICC for independent component cities
+	CityClass   = Attribute{"city_class", []string{"CC", "HUC", "ICC"}}
+	IncomeClass = Attribute{"income_class", []string{"1st", "2nd", "3rd", "4th", "5th", "6th", "Special"}}
+	// UrbanRural is U for urban and R for rural barangays
+	UrbanRural = Attribute{"urban_rural", []string{"U", "R"}}
+	// CityMuniStatus marks the provincial capitals
+	CityMuniStatus = Attribute{"status", []string{"Capital"}}
+	// BarangayStatus marks the poblacions, the town centers
+	BarangayStatus = Attribute{"status", []string{"Pob."}}
+)
+
+// The attributes each list can be filtered on
+var (
+	ProvinceAttributes = []Attribute{IncomeClass}
+	CityMuniAttributes = []Attribute{CityClass, IncomeClass, CityMuniStatus}
+	// MunicipalityAttributes are CityMuniAttributes without the city class
+	MunicipalityAttributes = []Attribute{IncomeClass, CityMuniStatus}
+	BarangayAttributes     = []Attribute{UrbanRural, BarangayStatus}
+)
+
+// AttributeValue returns the value params filters an attribute by, empty
+// when it isn't filtered
+func (p PaginationParams) AttributeValue(name string) string {
+	switch name {
+	case CityClass.Name:
+		return p.CityClass
+	case IncomeClass.Name:
+		return p.IncomeClass
+	case UrbanRural.Name:
+		return p.UrbanRural
+	case "status":
+		return p.Status
+	}
+	return ""
+}
+
+// SetAttribute sets the value params filters an attribute by
+func (p *PaginationParams) SetAttribute(name, value string) {
+	switch name {
+	case CityClass.Name:
+		p.CityClass = value
+	case IncomeClass.Name:
+		p.IncomeClass = value
+	case UrbanRural.Name:
+		p.UrbanRural = value
+	case "status":
+		p.Status = value
+	}
+}
+
+// MatchAttribute reports whether value, as written in the masterlist,
+// matches the filter want. Income classes carry footnotes, e.g. "1st*" or
+// "1st (as Mun)", which match their class.
+func MatchAttribute(name, want, value string) bool {
+	if value == want {
+		return true
+	}
+
+	return name == IncomeClass.Name &&
+		strings.HasPrefix(value, want) &&
+		strings.ContainsAny(value[len(want):len(want)+1], "* ")
+}
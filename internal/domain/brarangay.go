@@ -1,11 +1,19 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/filter"
+)
 
 type Barangay struct {
-	PsgcCode     string `json:"psgc_code"`
-	CityMuniCode string `json:"city_muni_code"`
-	Name         string `json:"name"`
+	PsgcCode       string `json:"psgc_code"`
+	CityMuniCode   string `json:"city_muni_code"`
+	Name           string `json:"name"`
+	UrbanRural     string `json:"urban_rural"`
+	Status         string `json:"status"`
+	Population2015 int    `json:"population_2015"`
+	Population2020 int    `json:"population_2020"`
 } //@name Barangay
 //? comment above is for renaming stuct
 
@@ -15,10 +23,31 @@ type PaginatedBarangay struct {
 } //@name PaginatedBarangay
 //? comment above is for renaming stuct
 
+// BarangaySortFields are the fields a list of barangays can be sorted by
+var BarangaySortFields = []string{"psgc_code", "name", "population_2015", "population_2020"}
+
+// BarangayFilterFields are the fields a list of barangays can be filtered on
+var BarangayFilterFields = filter.Fields{
+	"psgc_code":       {Kind: filter.String},
+	"citmun_code":     {Kind: filter.String},
+	"name":            {Kind: filter.String},
+	"urban_rural":     {Kind: filter.String},
+	"status":          {Kind: filter.String},
+	"population_2015": {Kind: filter.Number},
+	"population_2020": {Kind: filter.Number},
+}
+
 // BarangayRepository represents the barangay's repository contract
 type BarangayRepository interface {
 	GetAll(ctx context.Context, params PaginationParams) (PaginatedBarangay, error)
 	GetById(ctx context.Context, psgcCode string) (Barangay, error)
+	// GetByIds returns the items with the given codes, unknown codes are skipped
+	GetByIds(ctx context.Context, psgcCodes []string) ([]Barangay, error)
+	// GetByParents returns the children of every given parent code, as listed
+	// with PaginationParams.Parent
+	GetByParents(ctx context.Context, parentCodes []string) ([]Barangay, error)
+	// Each calls fn for every item matching params, without paginating
+	Each(ctx context.Context, params PaginationParams, fn func(item Barangay) error) error
 
 	Create(ctx context.Context, reg *Masterlist) error
 }
@@ -1,12 +1,21 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/filter"
+)
 
 type CityMuni struct {
-	PsgcCode string `json:"psgc_code"`
-	ProvCode string `json:"prov_code"`
-	Name     string `json:"name"`
-	Level    string `json:"level"`
+	PsgcCode       string `json:"psgc_code"`
+	ProvCode       string `json:"prov_code"`
+	Name           string `json:"name"`
+	Level          string `json:"level"`
+	CityClass      string `json:"city_class"`
+	IncomeClass    string `json:"income_class"`
+	Status         string `json:"status"`
+	Population2015 int    `json:"population_2015"`
+	Population2020 int    `json:"population_2020"`
 } //@name CityMuni
 //? comment above is for renaming stuct
 
@@ -16,13 +25,47 @@ type PaginatedCityMuni struct {
 } //@name PaginatedCityMuni
 //? comment above is for renaming stuct
 
+// CityMuniSortFields are the fields a list of cities/municipalities can be sorted by
+var CityMuniSortFields = []string{"psgc_code", "name", "level", "population_2015", "population_2020"}
+
+// CityMuniFilterFields are the fields a list of cities/municipalities can be filtered on
+var CityMuniFilterFields = filter.Fields{
+	"psgc_code":       {Kind: filter.String},
+	"prov_code":       {Kind: filter.String},
+	"name":            {Kind: filter.String},
+	"level":           {Kind: filter.String},
+	"city_class":      {Kind: filter.String},
+	"income_class":    {Kind: filter.String, Match: filter.Footnoted},
+	"status":          {Kind: filter.String},
+	"population_2015": {Kind: filter.Number},
+	"population_2020": {Kind: filter.Number},
+}
+
+// CityMuniUnitFilterFields are CityMuniFilterFields named like the v2 units,
+// whose parent_code is the province or, outside of one, the region, like
+// ParentCode
+var CityMuniUnitFilterFields = func() filter.Fields {
+	fields := CityMuniFilterFields.Rename("prov_code", "parent_code")
+	fields["parent_code"] = filter.Field{Kind: filter.String, Column: "prov_code", Match: filter.RegionParent}
+	return fields
+}()
+
 // CityMuniRepository represents the cityMuni's repository contract
 type CityMuniRepository interface {
 	GetAll(ctx context.Context, params PaginationParams) (PaginatedCityMuni, error)
 	GetById(ctx context.Context, psgcCode string) (CityMuni, error)
+	// GetByIds returns the items with the given codes, unknown codes are skipped
+	GetByIds(ctx context.Context, psgcCodes []string) ([]CityMuni, error)
+	// GetByParents returns the children of every given parent code, as listed
+	// with PaginationParams.Parent
+	GetByParents(ctx context.Context, parentCodes []string) ([]CityMuni, error)
+	// Each calls fn for every item matching params, without paginating
+	Each(ctx context.Context, params PaginationParams, fn func(item CityMuni) error) error
 	GetAllCity(ctx context.Context, params PaginationParams) (PaginatedCityMuni, error)
+	EachCity(ctx context.Context, params PaginationParams, fn func(item CityMuni) error) error
 	GetCityById(ctx context.Context, psgcCode string) (CityMuni, error)
 	GetAllMunicipality(ctx context.Context, params PaginationParams) (PaginatedCityMuni, error)
+	EachMunicipality(ctx context.Context, params PaginationParams, fn func(item CityMuni) error) error
 	GetMunicipalityById(ctx context.Context, psgcCode string) (CityMuni, error)
 
 	Create(ctx context.Context, reg *Masterlist) error
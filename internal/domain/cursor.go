@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// Cursor marks a row in a sorted list. Clients only ever see it encoded, as
+// the opaque next_cursor and prev_cursor strings.
+type Cursor struct {
+	Sort     string      `json:"s"`
+	Order    string      `json:"o"`
+	Value    interface{} `json:"v,omitempty"` // Value of the sort field, unset when sorting by psgc_code
+	PsgcCode string      `json:"k"`
+	Backward bool        `json:"b,omitempty"` // Backward is set on cursors that page towards the start
+}
+
+// wireCursor is the encoded form of a Cursor. JSON would turn the Latin-1
+// bytes of masterlist names into replacement characters, and the cursor
+// would then no longer match its row, so such a value is kept as bytes.
+type wireCursor struct {
+	Cursor
+	Bytes []byte `json:"r,omitempty"`
+}
+
+// Encode returns the opaque form of the cursor
+func (c Cursor) Encode() string {
+	wire := wireCursor{Cursor: c}
+	if s, ok := c.Value.(string); ok && !utf8.ValidString(s) {
+		wire.Value, wire.Bytes = nil, []byte(s)
+	}
+
+	b, _ := json.Marshal(wire)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses a cursor created by Cursor.Encode
+func DecodeCursor(s string) (Cursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	// Keep numbers exact, population counts are compared against integer columns
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var wire wireCursor
+	if err := dec.Decode(&wire); err != nil || wire.PsgcCode == "" {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	c := wire.Cursor
+	if wire.Bytes != nil {
+		c.Value = string(wire.Bytes)
+	}
+
+	if n, ok := c.Value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			c.Value = i
+		} else if f, err := n.Float64(); err == nil {
+			c.Value = f
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"psgc_code", Cursor{Sort: "psgc_code", Order: OrderAsc, PsgcCode: "0100000000"}},
+		{"string value", Cursor{Sort: "name", Order: OrderDesc, Value: "Las Pi\xf1as", PsgcCode: "1380100000"}},
+		{"integer value", Cursor{Sort: "population_2020", Order: OrderAsc, Value: int64(1776344), PsgcCode: "1380600000"}},
+		{"backward", Cursor{Sort: "population_2015", Order: OrderDesc, Value: int64(0), PsgcCode: "0102801001", Backward: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeCursor(tt.cursor.Encode())
+			if err != nil {
+				t.Fatalf("DecodeCursor: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.cursor) {
+				t.Errorf("DecodeCursor(Encode()) = %#v, want %#v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{"empty", ""},
+		{"not base64", "not a cursor!"},
+		{"not json", encode("psgc_code")},
+		{"no psgc_code", encode(`{"s":"name","o":"asc","v":"Abra"}`)},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte(`{"s":"psgc_code","o":"asc","k":"0100000000"}`))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeCursor(tt.s); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("DecodeCursor(%q) error = %v, want ErrInvalidCursor", tt.s, err)
+			}
+		})
+	}
+}
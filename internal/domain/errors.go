@@ -7,4 +7,6 @@ var (
 	ErrNotFound = errors.New("requested item was not found")
 	// ErrConflict will be returned if the item being persisted already exists
 	ErrConflict = errors.New("item already exists")
+	// ErrInvalidCursor will be returned if a pagination cursor can't be decoded
+	ErrInvalidCursor = errors.New("cursor is invalid")
 )
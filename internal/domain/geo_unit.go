@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/filter"
+)
+
+// Geographic levels as written in the PSGC masterlist
+const (
+	LevelRegion       = "Reg"
+	LevelProvince     = "Prov"
+	LevelCity         = "City"
+	LevelMunicipality = "Mun"
+	LevelSGU          = "SGU" // Special Geographic Area
+	LevelBarangay     = "Bgy"
+)
+
+// Levels lists the geographic levels from the top of the hierarchy down
+var Levels = []string{
+	LevelRegion,
+	LevelProvince,
+	LevelCity,
+	LevelMunicipality,
+	LevelSGU,
+	LevelBarangay,
+}
+
+// DescendantLevels are the levels a unit may be a descendant at, every
+// level but the regions
+var DescendantLevels = Levels[1:]
+
+type GeoUnit struct {
+	PsgcCode       string `json:"psgc_code"`
+	Name           string `json:"name"`
+	Level          string `json:"level"`
+	ParentCode     string `json:"parent_code"`
+	Population2015 int    `json:"population_2015"`
+	Population2020 int    `json:"population_2020"`
+} //@name GeoUnit
+//? comment above is for renaming stuct
+
+type PaginatedGeoUnit struct {
+	MetaData MetaData  `json:"metadata"`
+	Data     []GeoUnit `json:"data"`
+} //@name PaginatedGeoUnit
+//? comment above is for renaming stuct
+
+// TreeNode is a unit with the units below it, as written by the tree
+// export. Nodes also carry the attributes of their level's table.
+type TreeNode struct {
+	PsgcCode string     `json:"psgc_code"`
+	Name     string     `json:"name"`
+	Level    string     `json:"level"`
+	Children []TreeNode `json:"children"`
+} //@name TreeNode
+//? comment above is for renaming stuct
+
+// GeoUnitSortFields are the fields a list of geographic units can be sorted by
+var GeoUnitSortFields = []string{"psgc_code", "name", "level", "population_2015", "population_2020"}
+
+// GeoUnitFilterFields are the fields a list of geographic units can be filtered on
+var GeoUnitFilterFields = filter.Fields{
+	"psgc_code":       {Kind: filter.String},
+	"name":            {Kind: filter.String},
+	"level":           {Kind: filter.String},
+	"parent_code":     {Kind: filter.String},
+	"population_2015": {Kind: filter.Number},
+	"population_2020": {Kind: filter.Number},
+}
+
+// HierarchyRepository represents the contract of the unified geographic
+// hierarchy, where every level is a GeoUnit
+type HierarchyRepository interface {
+	// GetAll returns the units of a level, or of every level when level is
+	// empty. params.Parent limits them to the units right below a unit.
+	GetAll(ctx context.Context, level string, params PaginationParams) (PaginatedGeoUnit, error)
+	// Each calls fn for every unit GetAll would return, without paginating
+	Each(ctx context.Context, level string, params PaginationParams, fn func(item GeoUnit) error) error
+	GetById(ctx context.Context, psgcCode string) (GeoUnit, error)
+	// GetByIds returns the units with the given codes, unknown codes are skipped
+	GetByIds(ctx context.Context, psgcCodes []string) ([]GeoUnit, error)
+	// Ancestors returns the units above psgcCode, starting from its region
+	Ancestors(ctx context.Context, psgcCode string) ([]GeoUnit, error)
+	// Children returns the units right below psgcCode
+	Children(ctx context.Context, psgcCode string, params PaginationParams) (PaginatedGeoUnit, error)
+	// Descendants returns the units of a level anywhere below psgcCode, or of
+	// every level when level is empty
+	Descendants(ctx context.Context, psgcCode string, level string, params PaginationParams) (PaginatedGeoUnit, error)
+	// EachDescendant calls fn for every unit Descendants would return, without
+	// paginating
+	EachDescendant(ctx context.Context, psgcCode string, level string, params PaginationParams, fn func(item GeoUnit) error) error
+
+	Create(ctx context.Context, reg *Masterlist) error
+	// BuildClosure fills the ancestry of every unit, it runs once all units
+	// are created
+	BuildClosure(ctx context.Context) error
+}
+
+// Option is a unit as a choice of a select widget
+type Option struct {
+	Value string `json:"value"`
+	Label string `json:"label"`
+} //@name Option
+//? comment above is for renaming stuct
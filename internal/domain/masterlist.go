@@ -1,9 +1,45 @@
 package domain
 
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
 type Masterlist struct {
-	PsgcCode string `csv:"10-digit PSGC"       json:"psgc_code"`
-	Name     string `csv:"Name"                json:"name"`
-	Code     string `csv:"Correspondence Code" json:"-"`
-	Level    string `csv:"Geographic Level"    json:"-"`
+	PsgcCode       string     `csv:"10-digit PSGC"                     json:"psgc_code"`
+	Name           string     `csv:"Name"                              json:"name"`
+	Code           string     `csv:"Correspondence Code"               json:"-"`
+	Level          string     `csv:"Geographic Level"                  json:"-"`
+	CityClass      string     `csv:"City Class"                        json:"-"`
+	IncomeClass    string     `csv:"Income Classification"             json:"-"`
+	UrbanRural     string     `csv:"Urban / Rural (based on 2020 CPH)" json:"-"`
+	Population2015 Population `csv:"2015 Population"                   json:"-"`
+	Population2020 Population `csv:"2020 Population"                   json:"-"`
+	Status         string     `csv:"Status"                            json:"-"`
+	ParentCode     string     `csv:"-"                                 json:"-"` // ParentCode is resolved by the generator
 } //@name Masterlist
 //? comment above is for renaming stuct
+
+// populationPattern matches the count in cells like " 1,792 " or
+// " (excluding CITY OF ANGELES) 2,198,110 "
+var populationPattern = regexp.MustCompile(`[0-9][0-9,]*`)
+
+// Population is a census count read from the masterlist, blank and "-" cells are 0
+type Population int
+
+func (p *Population) UnmarshalCSV(value string) error {
+	matches := populationPattern.FindAllString(value, -1)
+	if len(matches) == 0 {
+		*p = 0
+		return nil
+	}
+
+	count, err := strconv.Atoi(strings.ReplaceAll(matches[len(matches)-1], ",", ""))
+	if err != nil {
+		return err
+	}
+
+	*p = Population(count)
+	return nil
+}
@@ -1,11 +1,18 @@
 package domain
 
+import "github.com/Brix101/psgc-tool/internal/filter"
+
 type MetaData struct {
-	Page       int `json:"page"       example:"1"`
-	TotalPages int `json:"total_pages" example:"10"`
-	PerPage    int `json:"per_page"    example:"1000"`
-	TotalItems int `json:"total_items" example:"10000"`
-	ItemCount  int `json:"item_count"  example:"1000"`
+	Page       int  `json:"page"       example:"1"`
+	TotalPages int  `json:"total_pages" example:"10"`
+	PerPage    int  `json:"per_page"    example:"1000"`
+	TotalItems int  `json:"total_items" example:"10000"`
+	ItemCount  int  `json:"item_count"  example:"1000"`
+	HasNext    bool `json:"has_next"    example:"true"`
+	HasPrev    bool `json:"has_prev"    example:"false"`
+	// NextCursor and PrevCursor continue the list from the last and first item of this page
+	NextCursor string `json:"next_cursor,omitempty" example:"eyJzIjoicHNnY19jb2RlIiwibyI6ImFzYyIsImsiOiIwMTAwMDAwMDAwIn0"`
+	PrevCursor string `json:"prev_cursor,omitempty" example:"eyJzIjoicHNnY19jb2RlIiwibyI6ImFzYyIsImsiOiIwMTAwMDAwMDAwIiwiYiI6dHJ1ZX0"`
 } //@name MetaData
 //? comment above is for renaming stuct
 
@@ -13,5 +20,31 @@ type PaginationParams struct {
 	Page    int    `json:"page"    example:"1"      validate:"gte=0"`
 	PerPage int    `json:"per_page" example:"1000"   validate:"lte=1000"`
 	Keyword string `json:"keyword"  example:"keyword"` // Keyword is used for filtering
+
+	// Sort is the field used for ordering, each resource has its own set of sortable fields
+	Sort string `json:"sort"  example:"psgc_code"`
+	// Order is the sort direction
+	Order string `json:"order" example:"asc" enums:"asc,desc" validate:"oneof=asc desc"`
+	// Parent limits the list to the children of a psgc_code, it isn't used for regions
+	Parent string `json:"parent" example:"0700000000" validate:"omitempty,numeric,len=10"`
+	// Cursor is a next_cursor or prev_cursor from an earlier response, it replaces page
+	Cursor string `json:"cursor" example:""`
+
+	// The attribute filters, each list supports some of them and documents
+	// them itself
+	CityClass   string `json:"city_class"   swaggerignore:"true"`
+	IncomeClass string `json:"income_class" swaggerignore:"true"`
+	UrbanRural  string `json:"urban_rural"  swaggerignore:"true"`
+	Status      string `json:"status"       swaggerignore:"true"`
+	// Filter is the parsed filter expression, over the fields of the list
+	Filter filter.Expr `json:"-" swaggerignore:"true"`
 } //@name PaginationParams
 // INFO? comment above is for renaming stuct
+
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// PerPageAll is the PerPage of a request for a whole list, per_page=all
+const PerPageAll = -1
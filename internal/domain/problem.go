@@ -0,0 +1,39 @@
+package domain
+
+// Problem is an error response, an RFC 7807 problem detail with the API's
+// own members
+type Problem struct {
+	Type     string `json:"type"     example:"about:blank"`
+	Title    string `json:"title"    example:"Bad Request"`
+	Status   int    `json:"status"   example:"400"`
+	Detail   string `json:"detail"   example:"per_page should be less than 1000."`
+	Instance string `json:"instance" example:"/api/regions"`
+
+	// Code is a stable identifier of the kind of error
+	Code      string `json:"code"                 example:"invalid_parameter"`
+	Message   string `json:"message"              example:"per_page should be less than 1000."`
+	RequestID string `json:"request_id,omitempty" example:"host/abcdef-000001"`
+	// Details lists the invalid parameters of a validation error
+	Details []FieldError `json:"details,omitempty"`
+} //@name Problem
+//? comment above is for renaming stuct
+
+// FieldError is a parameter that failed validation
+type FieldError struct {
+	Field      string `json:"field"      example:"per_page"`
+	Constraint string `json:"constraint" example:"lte=1000"`
+	Message    string `json:"message"    example:"per_page should be less than 1000."`
+	// Position is where the error is in a filter expression, from 1
+	Position int `json:"position,omitempty" example:"12"`
+} //@name FieldError
+//? comment above is for renaming stuct
+
+// Problem codes
+const (
+	CodeInvalidParameter = "invalid_parameter"
+	CodeNotFound         = "not_found"
+	CodeNotAcceptable    = "not_acceptable"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeTooManyRequests  = "too_many_requests"
+	CodeInternal         = "internal_error"
+)
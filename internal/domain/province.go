@@ -1,11 +1,18 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/filter"
+)
 
 type Province struct {
-	PsgcCode string `json:"psgc_code"`
-	RegCode  string `json:"regCode"`
-	Name     string `json:"name"`
+	PsgcCode       string `json:"psgc_code"`
+	RegCode        string `json:"regCode"`
+	Name           string `json:"name"`
+	IncomeClass    string `json:"income_class"`
+	Population2015 int    `json:"population_2015"`
+	Population2020 int    `json:"population_2020"`
 } //@name Province
 //? comment above is for renaming stuct
 
@@ -15,10 +22,30 @@ type PaginatedProvince struct {
 } //@name PaginatedProvince
 //? comment above is for renaming stuct
 
+// ProvinceSortFields are the fields a list of provinces can be sorted by
+var ProvinceSortFields = []string{"psgc_code", "name", "population_2015", "population_2020"}
+
+// ProvinceFilterFields are the fields a list of provinces can be filtered on
+var ProvinceFilterFields = filter.Fields{
+	"psgc_code":       {Kind: filter.String},
+	"reg_code":        {Kind: filter.String},
+	"name":            {Kind: filter.String},
+	"income_class":    {Kind: filter.String, Match: filter.Footnoted},
+	"population_2015": {Kind: filter.Number},
+	"population_2020": {Kind: filter.Number},
+}
+
 // ProvinceRepository represents the province's repository contract
 type ProvinceRepository interface {
 	GetAll(ctx context.Context, params PaginationParams) (PaginatedProvince, error)
 	GetById(ctx context.Context, psgcCode string) (Province, error)
+	// GetByIds returns the items with the given codes, unknown codes are skipped
+	GetByIds(ctx context.Context, psgcCodes []string) ([]Province, error)
+	// GetByParents returns the children of every given parent code, as listed
+	// with PaginationParams.Parent
+	GetByParents(ctx context.Context, parentCodes []string) ([]Province, error)
+	// Each calls fn for every item matching params, without paginating
+	Each(ctx context.Context, params PaginationParams, fn func(item Province) error) error
 
 	Create(ctx context.Context, reg *Masterlist) error
 }
@@ -0,0 +1,51 @@
+package domain
+
+// Ranking metrics, the growth rate ranks the units with a 2015 population
+const (
+	MetricPopulation2015   = "population_2015"
+	MetricPopulation2020   = "population_2020"
+	MetricPopulationChange = "population_change"
+	MetricGrowthRate       = "growth_rate"
+)
+
+// RankingMetrics are the metrics units can be ranked by
+var RankingMetrics = []string{
+	MetricPopulation2015,
+	MetricPopulation2020,
+	MetricPopulationChange,
+	MetricGrowthRate,
+}
+
+// RankingParams selects the units of a ranking
+type RankingParams struct {
+	Level  string
+	Metric string
+	// Scope limits the ranking to the units below a unit, the whole country
+	// when empty
+	Scope string
+	Order string
+	// Limit is the last rank returned, units tied at it are all returned
+	Limit int
+}
+
+// RankedUnit is a unit with its rank and every ranking metric. Units
+// showing the same value of the metric, e.g. the same rounded growth rate,
+// share a rank and the next rank is skipped, e.g. 1, 2, 2, 4.
+type RankedUnit struct {
+	Rank             int     `json:"rank"`
+	PsgcCode         string  `json:"psgc_code"`
+	Name             string  `json:"name"`
+	Level            string  `json:"level"`
+	ParentCode       string  `json:"parent_code"`
+	Population2015   int     `json:"population_2015"`
+	Population2020   int     `json:"population_2020"`
+	PopulationChange int     `json:"population_change"`
+	GrowthRate       float64 `json:"growth_rate"`
+} //@name RankedUnit
+//? comment above is for renaming stuct
+
+// RankingResponse is the list of a ranking
+type RankingResponse struct {
+	Data []RankedUnit `json:"data"`
+} //@name RankingResponse
+//? comment above is for renaming stuct
@@ -1,10 +1,16 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/filter"
+)
 
 type Region struct {
-	PsgcCode string `json:"psgc_code"`
-	Name     string `json:"name"`
+	PsgcCode       string `json:"psgc_code"`
+	Name           string `json:"name"`
+	Population2015 int    `json:"population_2015"`
+	Population2020 int    `json:"population_2020"`
 } //@name Region
 //? comment above is for renaming stuct
 
@@ -14,10 +20,25 @@ type PaginatedRegion struct {
 } //@name PaginatedRegion
 //? comment above is for renaming stuct
 
+// RegionSortFields are the fields a list of regions can be sorted by
+var RegionSortFields = []string{"psgc_code", "name", "population_2015", "population_2020"}
+
+// RegionFilterFields are the fields a list of regions can be filtered on
+var RegionFilterFields = filter.Fields{
+	"psgc_code":       {Kind: filter.String},
+	"name":            {Kind: filter.String},
+	"population_2015": {Kind: filter.Number},
+	"population_2020": {Kind: filter.Number},
+}
+
 // RegionRepository represents the region's repository contract
 type RegionRepository interface {
 	GetAll(ctx context.Context, params PaginationParams) (PaginatedRegion, error)
 	GetById(ctx context.Context, psgcCode string) (Region, error)
+	// GetByIds returns the items with the given codes, unknown codes are skipped
+	GetByIds(ctx context.Context, psgcCodes []string) ([]Region, error)
+	// Each calls fn for every item matching params, without paginating
+	Each(ctx context.Context, params PaginationParams, fn func(item Region) error) error
 
 	Create(ctx context.Context, reg *Masterlist) error
 }
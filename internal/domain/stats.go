@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"math"
+)
+
+// CensusInterval is the years between the 2015 census (August 1) and the
+// 2020 census (May 1), the interval PSA computes growth rates over
+const CensusInterval = 4.75
+
+// Stats summarizes a unit and the units below it, or the whole country when
+// PsgcCode is empty. The counts and the urban/rural splits are of every unit
+// below, e.g. all the barangays of a region.
+type Stats struct {
+	PsgcCode string `json:"psgc_code"`
+	Name     string `json:"name"`
+	Level    string `json:"level"`
+
+	Regions        int `json:"regions"`
+	Provinces      int `json:"provinces"`
+	Cities         int `json:"cities"`
+	Municipalities int `json:"municipalities"`
+	SGUs           int `json:"sgus"`
+	Barangays      int `json:"barangays"`
+
+	Population2015   int `json:"population_2015"`
+	Population2020   int `json:"population_2020"`
+	PopulationChange int `json:"population_change"`
+	// GrowthRate is the annual population growth rate from 2015 to 2020 in
+	// percent, 0 without a 2015 population
+	GrowthRate float64 `json:"growth_rate"`
+
+	// Barangays without an urban/rural classification are in neither split
+	UrbanBarangays      int `json:"urban_barangays"`
+	RuralBarangays      int `json:"rural_barangays"`
+	UrbanPopulation2020 int `json:"urban_population_2020"`
+	RuralPopulation2020 int `json:"rural_population_2020"`
+} //@name Stats
+//? comment above is for renaming stuct
+
+// GrowthRate is the annual growth rate in percent from population2015 to
+// population2020, rounded to two decimals as PSA publishes it
+func GrowthRate(population2015, population2020 int) float64 {
+	if population2015 <= 0 {
+		return 0
+	}
+
+	rate := (math.Pow(float64(population2020)/float64(population2015), 1/CensusInterval) - 1) * 100
+	return math.Round(rate*100) / 100
+}
+
+// StatsRepository represents the contract of the summary tables, which the
+// generator fills once every unit is loaded
+type StatsRepository interface {
+	// Get returns the stats of a unit and the units below it
+	Get(ctx context.Context, psgcCode string) (Stats, error)
+	// National returns the stats of the whole country
+	National(ctx context.Context) (Stats, error)
+	// Rankings returns the units of a level ranked by a metric
+	Rankings(ctx context.Context, params RankingParams) ([]RankedUnit, error)
+
+	// Build computes the stats of every unit, it runs once the closure of
+	// the hierarchy is built
+	Build(ctx context.Context) error
+}
+
+// StatsResponse is the v2 envelope of stats
+type StatsResponse struct {
+	Data Stats `json:"data"`
+} //@name StatsResponse
+//? comment above is for renaming stuct
@@ -0,0 +1,136 @@
+package domain
+
+import "strings"
+
+// Unit is a geographic unit of any level as the v2 API sends it. Every level
+// has the same fields, the attributes a level doesn't have are left out of
+// JSON and empty in CSV.
+type Unit struct {
+	PsgcCode string `json:"psgc_code"`
+	Name     string `json:"name"`
+	Level    string `json:"level"`
+	// ParentCode is the unit right above, empty for regions. Independent
+	// cities, Pateros and SGUs are right below their region.
+	ParentCode  string `json:"parent_code"`
+	IncomeClass string `json:"income_class,omitempty"`
+	CityClass   string `json:"city_class,omitempty"`
+	UrbanRural  string `json:"urban_rural,omitempty"`
+	Status      string `json:"status,omitempty"`
+
+	Population2015 int `json:"population_2015"`
+	Population2020 int `json:"population_2020"`
+} //@name Unit
+//? comment above is for renaming stuct
+
+// UnitResponse is the v2 envelope of a single unit
+type UnitResponse struct {
+	Data Unit `json:"data"`
+} //@name UnitResponse
+//? comment above is for renaming stuct
+
+// PaginatedUnit is the v2 envelope of a list of units
+type PaginatedUnit struct {
+	MetaData MetaData `json:"metadata"`
+	Data     []Unit   `json:"data"`
+} //@name PaginatedUnit
+//? comment above is for renaming stuct
+
+// UnitTreeNode is a v2 unit with the units below it
+type UnitTreeNode struct {
+	Unit
+	Children []UnitTreeNode `json:"children"`
+} //@name UnitTreeNode
+//? comment above is for renaming stuct
+
+// RegionOf is the region code of any psgc_code, its first two digits
+func RegionOf(psgcCode string) string {
+	return prefixCode(psgcCode, 2)
+}
+
+// ProvinceOf is the province code of a psgc_code below the province level,
+// its first five digits
+func ProvinceOf(psgcCode string) string {
+	return prefixCode(psgcCode, 5)
+}
+
+// prefixCode keeps the first digits of a psgc_code and zeroes the rest,
+// empty for a code too short to have them
+func prefixCode(psgcCode string, digits int) string {
+	if len(psgcCode) < digits {
+		return ""
+	}
+
+	return psgcCode[:digits] + strings.Repeat("0", len(psgcCode)-digits)
+}
+
+func (r Region) Unit() Unit {
+	return Unit{
+		PsgcCode:       r.PsgcCode,
+		Name:           r.Name,
+		Level:          LevelRegion,
+		Population2015: r.Population2015,
+		Population2020: r.Population2020,
+	}
+}
+
+func (p Province) Unit() Unit {
+	return Unit{
+		PsgcCode:       p.PsgcCode,
+		Name:           p.Name,
+		Level:          LevelProvince,
+		ParentCode:     p.RegCode,
+		IncomeClass:    p.IncomeClass,
+		Population2015: p.Population2015,
+		Population2020: p.Population2020,
+	}
+}
+
+// ParentCode is the province of a city/municipality, or its region for
+// independent cities, Pateros and SGUs, which have no province
+func (c CityMuni) ParentCode() string {
+	if c.ProvCode != "" {
+		return c.ProvCode
+	}
+
+	return RegionOf(c.PsgcCode)
+}
+
+func (c CityMuni) Unit() Unit {
+	return Unit{
+		PsgcCode:       c.PsgcCode,
+		Name:           c.Name,
+		Level:          c.Level,
+		ParentCode:     c.ParentCode(),
+		IncomeClass:    c.IncomeClass,
+		CityClass:      c.CityClass,
+		Status:         c.Status,
+		Population2015: c.Population2015,
+		Population2020: c.Population2020,
+	}
+}
+
+func (b Barangay) Unit() Unit {
+	return Unit{
+		PsgcCode:       b.PsgcCode,
+		Name:           b.Name,
+		Level:          LevelBarangay,
+		ParentCode:     b.CityMuniCode,
+		UrbanRural:     b.UrbanRural,
+		Status:         b.Status,
+		Population2015: b.Population2015,
+		Population2020: b.Population2020,
+	}
+}
+
+// Unit converts a unit of the unified hierarchy, which doesn't carry the
+// attributes of the levels
+func (g GeoUnit) Unit() Unit {
+	return Unit{
+		PsgcCode:       g.PsgcCode,
+		Name:           g.Name,
+		Level:          g.Level,
+		ParentCode:     g.ParentCode,
+		Population2015: g.Population2015,
+		Population2020: g.Population2020,
+	}
+}
@@ -0,0 +1,38 @@
+package domain
+
+import "testing"
+
+func TestParentCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		psgcCode string
+		provCode string
+		region   string
+		province string
+		parent   string
+	}{
+		{"municipality", "0102801000", "0102800000", "0100000000", "0102800000", "0102800000"},
+		{"independent city", "1380600000", "", "1300000000", "1380600000", "1300000000"},
+		{"SGU", "1999901000", "", "1900000000", "1999900000", "1900000000"},
+		{"too short", "1", "", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RegionOf(tt.psgcCode); got != tt.region {
+				t.Errorf("RegionOf(%q) = %q, want %q", tt.psgcCode, got, tt.region)
+			}
+			if got := ProvinceOf(tt.psgcCode); got != tt.province {
+				t.Errorf("ProvinceOf(%q) = %q, want %q", tt.psgcCode, got, tt.province)
+			}
+
+			c := CityMuni{PsgcCode: tt.psgcCode, ProvCode: tt.provCode}
+			if got := c.ParentCode(); got != tt.parent {
+				t.Errorf("ParentCode() = %q, want %q", got, tt.parent)
+			}
+			if got := c.Unit().ParentCode; got != tt.parent {
+				t.Errorf("Unit().ParentCode = %q, want %q", got, tt.parent)
+			}
+		})
+	}
+}
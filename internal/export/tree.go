@@ -0,0 +1,275 @@
+package export
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// MaxDepth is the depth of the deepest tree, region → province →
+// city/municipality → barangay
+const MaxDepth = 3
+
+// TreeWriter writes a unit and the units below it as one nested JSON
+// document. Every node is the unit's own fields plus its level and a
+// children array:
+//
+//	{"psgc_code":"0100000000","name":"Region I",...,"level":"Reg","children":[...]}
+//
+// Nodes are written as the rows are read, only the path from the root to the
+// current unit is held at a time so the whole country can be exported.
+type TreeWriter struct {
+	regRepo      domain.RegionRepository
+	provRepo     domain.ProvinceRepository
+	cityMuniRepo domain.CityMuniRepository
+	bgyRepo      domain.BarangayRepository
+	// units writes the nodes as domain.Unit, the v2 shape
+	units bool
+}
+
+func NewTreeWriter(
+	regRepo domain.RegionRepository,
+	provRepo domain.ProvinceRepository,
+	cityMuniRepo domain.CityMuniRepository,
+	bgyRepo domain.BarangayRepository,
+) *TreeWriter {
+	return &TreeWriter{
+		regRepo:      regRepo,
+		provRepo:     provRepo,
+		cityMuniRepo: cityMuniRepo,
+		bgyRepo:      bgyRepo,
+	}
+}
+
+// Units returns a TreeWriter writing every node as a domain.Unit, which has
+// the same fields at every level
+func (tw *TreeWriter) Units() *TreeWriter {
+	units := *tw
+	units.units = true
+
+	return &units
+}
+
+// Write writes the tree of psgcCode down to depth levels below it, depth 0
+// writes the unit alone. Returns domain.ErrNotFound if no unit has the code.
+//
+// The tree reaches w in buffered chunks as it is read, so after a failure w
+// may hold the start of it. Nothing is written before the unit is found.
+func (tw *TreeWriter) Write(ctx context.Context, w io.Writer, psgcCode string, depth int) error {
+	bw := bufio.NewWriter(w)
+
+	if err := tw.writeUnit(ctx, bw, psgcCode, depth); err != nil {
+		return err
+	}
+
+	if _, err := bw.WriteString("\n"); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// WriteAll writes the tree of every region as a JSON array, a failure may
+// leave the start of it in w like Write
+func (tw *TreeWriter) WriteAll(ctx context.Context, w io.Writer, depth int) error {
+	bw := bufio.NewWriter(w)
+
+	if err := tw.writeChildren(ctx, bw, func(ctx context.Context, fn func(node) error) error {
+		return tw.regRepo.Each(ctx, domain.PaginationParams{}, func(item domain.Region) error {
+			return fn(tw.regionNode(item))
+		})
+	}, depth+1); err != nil {
+		return err
+	}
+
+	if _, err := bw.WriteString("\n"); err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
+
+// node is a unit of any level with a way to list the units under it
+type node struct {
+	item     interface{}
+	level    string
+	children func(ctx context.Context, fn func(node) error) error
+}
+
+// writeUnit looks up the unit in the four tables, highest level first, and
+// writes its tree
+func (tw *TreeWriter) writeUnit(ctx context.Context, w *bufio.Writer, psgcCode string, depth int) error {
+	n, err := tw.find(ctx, psgcCode)
+	if err != nil {
+		return err
+	}
+
+	return tw.writeNode(ctx, w, n, depth)
+}
+
+func (tw *TreeWriter) find(ctx context.Context, psgcCode string) (node, error) {
+	if item, err := tw.regRepo.GetById(ctx, psgcCode); err == nil {
+		return tw.regionNode(item), nil
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return node{}, err
+	}
+
+	if item, err := tw.provRepo.GetById(ctx, psgcCode); err == nil {
+		return tw.provinceNode(item), nil
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return node{}, err
+	}
+
+	if item, err := tw.cityMuniRepo.GetById(ctx, psgcCode); err == nil {
+		return tw.cityMuniNode(item), nil
+	} else if !errors.Is(err, domain.ErrNotFound) {
+		return node{}, err
+	}
+
+	item, err := tw.bgyRepo.GetById(ctx, psgcCode)
+	if err != nil {
+		return node{}, err
+	}
+
+	return tw.barangayNode(item), nil
+}
+
+// regionNode lists the provinces of a region, then the cities and
+// municipalities that belong to no province
+func (tw *TreeWriter) regionNode(item domain.Region) node {
+	params := domain.PaginationParams{Parent: item.PsgcCode}
+
+	return node{
+		item:  item,
+		level: domain.LevelRegion,
+		children: func(ctx context.Context, fn func(node) error) error {
+			err := tw.provRepo.Each(ctx, params, func(item domain.Province) error {
+				return fn(tw.provinceNode(item))
+			})
+			if err != nil {
+				return err
+			}
+
+			return tw.cityMuniRepo.Each(ctx, params, func(item domain.CityMuni) error {
+				return fn(tw.cityMuniNode(item))
+			})
+		},
+	}
+}
+
+func (tw *TreeWriter) provinceNode(item domain.Province) node {
+	params := domain.PaginationParams{Parent: item.PsgcCode}
+
+	return node{
+		item:  item,
+		level: domain.LevelProvince,
+		children: func(ctx context.Context, fn func(node) error) error {
+			return tw.cityMuniRepo.Each(ctx, params, func(item domain.CityMuni) error {
+				return fn(tw.cityMuniNode(item))
+			})
+		},
+	}
+}
+
+func (tw *TreeWriter) cityMuniNode(item domain.CityMuni) node {
+	params := domain.PaginationParams{Parent: item.PsgcCode}
+
+	return node{
+		item: item,
+		// CityMuni carries its own level
+		children: func(ctx context.Context, fn func(node) error) error {
+			return tw.bgyRepo.Each(ctx, params, func(item domain.Barangay) error {
+				return fn(tw.barangayNode(item))
+			})
+		},
+	}
+}
+
+func (tw *TreeWriter) barangayNode(item domain.Barangay) node {
+	return node{
+		item:  item,
+		level: domain.LevelBarangay,
+		children: func(context.Context, func(node) error) error {
+			return nil
+		},
+	}
+}
+
+// writeNode writes the unit's JSON object, left open, followed by its level
+// and children
+func (tw *TreeWriter) writeNode(ctx context.Context, w *bufio.Writer, n node, depth int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	item, level := n.item, n.level
+	if tw.units {
+		// A unit has its own level
+		item, level = item.(interface{ Unit() domain.Unit }).Unit(), ""
+	}
+
+	res, err := json.Marshal(item)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(res[:len(res)-1]); err != nil {
+		return err
+	}
+
+	if level != "" {
+		if _, err := w.WriteString(`,"level":"` + level + `"`); err != nil {
+			return err
+		}
+	}
+
+	if _, err := w.WriteString(`,"children":`); err != nil {
+		return err
+	}
+
+	if depth > 0 {
+		err = tw.writeChildren(ctx, w, n.children, depth)
+	} else {
+		_, err = w.WriteString("[]")
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = w.WriteString("}")
+	return err
+}
+
+// writeChildren writes the units listed by children as a JSON array, each
+// one with depth-1 levels below it
+func (tw *TreeWriter) writeChildren(
+	ctx context.Context,
+	w *bufio.Writer,
+	children func(ctx context.Context, fn func(node) error) error,
+	depth int,
+) error {
+	if err := w.WriteByte('['); err != nil {
+		return err
+	}
+
+	first := true
+	err := children(ctx, func(child node) error {
+		if !first {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+
+		return tw.writeNode(ctx, w, child, depth-1)
+	})
+	if err != nil {
+		return err
+	}
+
+	return w.WriteByte(']')
+}
@@ -0,0 +1,142 @@
+package filter
+
+import (
+	"cmp"
+	"strings"
+)
+
+// Eval reports whether an item matches e. value returns the item's value
+// of a column, a string or an int, psgc_code included.
+func Eval(e Expr, value func(column string) interface{}) bool {
+	switch e := e.(type) {
+	case And:
+		return Eval(e.Left, value) && Eval(e.Right, value)
+	case Or:
+		return Eval(e.Left, value) || Eval(e.Right, value)
+	case Not:
+		return !Eval(e.X, value)
+	case Compare:
+		v := value(e.Column)
+		if e.Match == RegionParent {
+			v = regionParent(v, value("psgc_code"))
+		}
+		return e.match(v)
+	}
+	return false
+}
+
+// regionParent returns the parent code v or, when it is empty, the region of
+// the psgc code
+func regionParent(v, code interface{}) interface{} {
+	if s, _ := v.(string); s != "" {
+		return s
+	}
+	if code, _ := code.(string); len(code) >= 2 {
+		return code[:2] + "00000000"
+	}
+	return v
+}
+
+func (c Compare) match(v interface{}) bool {
+	if c.Kind == Number {
+		n := toInt64(v)
+		switch c.Op {
+		case In:
+			for _, value := range c.Values {
+				if n == value.(int64) {
+					return true
+				}
+			}
+			return false
+		}
+		return compareOp(c.Op, cmp.Compare(n, c.Values[0].(int64)))
+	}
+
+	s, _ := v.(string)
+	switch c.Op {
+	case In:
+		for _, value := range c.Values {
+			if value != nil && c.equals(s, value.(string)) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if c.Values[0] == nil {
+		return (s == "") == (c.Op == Eq)
+	}
+
+	switch c.Op {
+	case Eq:
+		return c.equals(s, c.Values[0].(string))
+	case Ne:
+		return !c.equals(s, c.Values[0].(string))
+	}
+
+	want := FoldCase(c.Values[0].(string))
+	switch c.Op {
+	case StartsWith:
+		return strings.HasPrefix(FoldCase(s), want)
+	case EndsWith:
+		return strings.HasSuffix(FoldCase(s), want)
+	case Contains:
+		return strings.Contains(FoldCase(s), want)
+	}
+	return compareOp(c.Op, strings.Compare(FoldCase(s), want))
+}
+
+// equals reports whether s equals want without case, or with a footnote for
+// a Footnoted field
+func (c Compare) equals(s, want string) bool {
+	s, want = FoldCase(s), FoldCase(want)
+	if s == want {
+		return true
+	}
+
+	return c.Match == Footnoted &&
+		strings.HasPrefix(s, want) &&
+		strings.ContainsAny(s[len(want):len(want)+1], "* ")
+}
+
+// compareOp applies an ordering operator to the result of a comparison
+func compareOp(op Op, c int) bool {
+	switch op {
+	case Eq:
+		return c == 0
+	case Ne:
+		return c != 0
+	case Gt:
+		return c > 0
+	case Ge:
+		return c >= 0
+	case Lt:
+		return c < 0
+	case Le:
+		return c <= 0
+	}
+	return false
+}
+
+// FoldCase lowers the ASCII letters of s and keeps every other byte, like
+// SQLite's NOCASE. strings.ToLower would turn the Latin-1 bytes of the
+// masterlist into replacement characters.
+func FoldCase(s string) string {
+	b := []byte(s)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+func toInt64(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int64:
+		return n
+	}
+	return 0
+}
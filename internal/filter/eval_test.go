@@ -0,0 +1,99 @@
+package filter
+
+import "testing"
+
+// testItem is a city outside of a province, with a footnoted income class
+// and a Latin-1 name
+var testItem = map[string]interface{}{
+	"psgc_code":       "1380100000",
+	"name":            "City of Las Pi\xf1as",
+	"level":           "City",
+	"prov_code":       "",
+	"income_class":    "1st*",
+	"population_2020": 606293,
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"name eq 'CITY OF LAS PI\xf1AS'", true},
+		{"name eq 'City of Las Pi\xd1as'", false}, // only ASCII folds
+		{"name ne 'City'", true},
+		{"name startswith 'city of'", true},
+		{"name endswith 'PI\xf1AS'", true},
+		{"name contains 'las'", true},
+		{"name contains 'pasig'", false},
+		{"name gt 'City of A'", true},
+		{"name lt 'city of a'", false},
+		{"level in ('Mun', 'city')", true},
+		{"level in ('Mun', 'SubMun')", false},
+		{"population_2020 gt 600000 and population_2020 le 606293", true},
+		{"population_2020 in (1, 606293)", true},
+		{"population_2020 lt 606293", false},
+		{"missing eq null", true},
+		{"name eq null", false},
+		{"name ne null", true},
+
+		// and binds tighter than or
+		{"level eq 'Mun' and name contains 'las' or population_2020 gt 0", true},
+		{"level eq 'Mun' and (name contains 'las' or population_2020 gt 0)", false},
+		{"not level eq 'Mun' and level eq 'City'", true},
+		{"not (level eq 'Mun' or level eq 'City')", false},
+
+		// The footnote of the income class
+		{"income_class eq '1st'", true},
+		{"income_class eq '1ST'", true},
+		{"income_class eq '1st*'", true},
+		{"income_class eq '1s'", false},
+		{"income_class ne '1st'", false},
+		{"income_class in ('2nd', '1st')", true},
+		{"income_class startswith '1st*'", true},
+		{"income_class gt '1st'", true}, // orders as written
+
+		// The region is the parent outside of a province
+		{"parent_code eq '1300000000'", true},
+		{"parent_code ne '1300000000'", false},
+		{"parent_code in ('0102800000', '1300000000')", true},
+		{"parent_code startswith '13'", true},
+		{"parent_code eq null", false},
+		{"prov_code eq null", true},
+	}
+
+	fields := Fields{"missing": {Kind: String}, "prov_code": {Kind: String}}
+	for name, field := range testFields {
+		fields[name] = field
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := Parse(tt.src, fields)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			got := Eval(e, func(column string) interface{} { return testItem[column] })
+			if got != tt.want {
+				t.Errorf("Eval() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalRegionParent(t *testing.T) {
+	e, err := Parse("parent_code eq '0102800000'", testFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := map[string]interface{}{"psgc_code": "0102801000", "prov_code": "0102800000"}
+	if !Eval(e, func(column string) interface{} { return item[column] }) {
+		t.Error("a municipality isn't a child of its province")
+	}
+
+	item["prov_code"] = "0102900000"
+	if Eval(e, func(column string) interface{} { return item[column] }) {
+		t.Error("a municipality is a child of another province")
+	}
+}
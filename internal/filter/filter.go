@@ -0,0 +1,151 @@
+// Package filter parses the filter expressions of the list endpoints, e.g.
+// level eq 'City' and population_2020 gt 500000 and name startswith 'San'.
+//
+// An expression may only name the fields of its resource, which map to
+// columns, and its values are literals. It is compiled to a parameterized
+// condition by the SQL repositories and evaluated by the in-memory ones.
+package filter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Op is a comparison operator
+type Op string
+
+const (
+	Eq         Op = "eq"
+	Ne         Op = "ne"
+	Gt         Op = "gt"
+	Ge         Op = "ge"
+	Lt         Op = "lt"
+	Le         Op = "le"
+	StartsWith Op = "startswith"
+	EndsWith   Op = "endswith"
+	Contains   Op = "contains"
+	In         Op = "in"
+)
+
+var ops = []Op{Eq, Ne, Gt, Ge, Lt, Le, StartsWith, EndsWith, Contains, In}
+
+// Kind is the type of a field's values
+type Kind int
+
+const (
+	String Kind = iota
+	Number
+)
+
+func (k Kind) String() string {
+	if k == Number {
+		return "number"
+	}
+	return "string"
+}
+
+// Match is how a string field's values equal a value, for eq, ne and in
+type Match int
+
+const (
+	// Exact values equal, without case
+	Exact Match = iota
+	// Footnoted values also equal with a footnote, like the income classes
+	// of the masterlist: '1st' equals "1st*" and "1st (as Mun)"
+	Footnoted
+	// RegionParent is a parent code which, when the column is empty, is the
+	// region of the item's psgc_code, like domain.CityMuni.ParentCode. It
+	// applies to every operator.
+	RegionParent
+)
+
+// Field is a field an expression may name
+type Field struct {
+	Kind Kind
+	// Column is the column of the field, the field's name when empty
+	Column string
+	Match  Match
+}
+
+// Fields are the fields of a resource by name, the whitelist of its
+// expressions
+type Fields map[string]Field
+
+// names returns the field names in order, for error messages
+func (fs Fields) names() []string {
+	names := make([]string, 0, len(fs))
+	for name := range fs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Rename returns a copy of fs where the field of column is named name
+// instead, e.g. reg_code as parent_code
+func (fs Fields) Rename(column, name string) Fields {
+	renamed := make(Fields, len(fs))
+	for n, field := range fs {
+		if field.Column == "" {
+			field.Column = n
+		}
+		if field.Column == column {
+			n = name
+		}
+		renamed[n] = field
+	}
+	return renamed
+}
+
+// Expr is a parsed expression: an And, an Or, a Not or a Compare
+type Expr interface {
+	expr()
+}
+
+// And matches when both sides match
+type And struct{ Left, Right Expr }
+
+// Or matches when either side matches
+type Or struct{ Left, Right Expr }
+
+// Not matches when X doesn't
+type Not struct{ X Expr }
+
+// Compare compares a column to literal values. Values are strings, int64s
+// or nil for null, there is one of them for every operator but In.
+//
+// Strings compare without ASCII case, like the NOCASE name columns and
+// LIKE, and by the Match of their field. Null is an empty or missing
+// string.
+type Compare struct {
+	Column string
+	Kind   Kind
+	Match  Match
+	Op     Op
+	Values []interface{}
+}
+
+func (And) expr()     {}
+func (Or) expr()      {}
+func (Not) expr()     {}
+func (Compare) expr() {}
+
+// Error is an invalid expression, Pos is where in it the error is, from 1
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("filter at position %d: %s", e.Pos, e.Msg)
+}
+
+// opNames lists the operators for error messages
+func opNames() string {
+	names := make([]string, len(ops))
+	for i, op := range ops {
+		names[i] = string(op)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,348 @@
+package filter
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// MaxLength is the longest expression Parse accepts, in bytes
+	MaxLength = 1000
+	// MaxDepth bounds the nesting of parentheses and not
+	MaxDepth = 20
+)
+
+// The grammar, keywords and operators are case-insensitive:
+//
+//	expr       = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" expr ")" | comparison
+//	comparison = field op value | field "in" "(" value { "," value } ")"
+//	value      = 'string' | integer | null
+//
+// A quote in a string is doubled, 'Bah''' is Bah'.
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokString
+	tokNumber
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind tokenKind
+	// text is the token as written, value the literal of a string or a
+	// number
+	text  string
+	value interface{}
+	pos   int // byte offset
+}
+
+// describe names the token in error messages
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "end of filter"
+	case tokString:
+		return t.text
+	}
+	return "'" + t.text + "'"
+}
+
+// keyword returns an identifier token in lower case, empty for any other
+// token
+func (t token) keyword() string {
+	if t.kind != tokIdent {
+		return ""
+	}
+	return strings.ToLower(t.text)
+}
+
+type parser struct {
+	src    string
+	fields Fields
+	tok    token
+	next   int // byte offset of the next token
+	depth  int
+}
+
+// Parse parses an expression over fields. Every error is an *Error.
+func Parse(src string, fields Fields) (Expr, error) {
+	p := &parser{src: src, fields: fields}
+	if len(src) > MaxLength {
+		return nil, p.errorAt(MaxLength, fmt.Sprintf("filter is longer than %d characters", MaxLength))
+	}
+	if strings.TrimSpace(src) == "" {
+		return nil, p.errorAt(0, "filter is empty")
+	}
+
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("unexpected %s, expected and, or or the end of filter", p.tok.describe())
+	}
+
+	return e, nil
+}
+
+// errorAt returns an *Error at a byte offset of the source
+func (p *parser) errorAt(offset int, msg string) *Error {
+	return &Error{Pos: utf8.RuneCountInString(p.src[:offset]) + 1, Msg: msg}
+}
+
+// errorf returns an *Error at the current token
+func (p *parser) errorf(format string, args ...interface{}) *Error {
+	return p.errorAt(p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+// advance reads the next token into p.tok
+func (p *parser) advance() error {
+	src := p.src
+	i := p.next
+	for i < len(src) && (src[i] == ' ' || src[i] == '\t' || src[i] == '\n' || src[i] == '\r') {
+		i++
+	}
+
+	start := i
+	if i == len(src) {
+		p.tok = token{kind: tokEOF, pos: i}
+		p.next = i
+		return nil
+	}
+
+	switch c := src[i]; {
+	case c == '(':
+		p.tok = token{kind: tokLParen, text: "(", pos: i}
+		i++
+	case c == ')':
+		p.tok = token{kind: tokRParen, text: ")", pos: i}
+		i++
+	case c == ',':
+		p.tok = token{kind: tokComma, text: ",", pos: i}
+		i++
+	case c == '\'':
+		var b strings.Builder
+		for i++; ; i++ {
+			if i == len(src) {
+				return p.errorAt(start, "unterminated string")
+			}
+			if src[i] == '\'' {
+				if i+1 < len(src) && src[i+1] == '\'' {
+					b.WriteByte('\'')
+					i++
+					continue
+				}
+				i++
+				break
+			}
+			b.WriteByte(src[i])
+		}
+		p.tok = token{kind: tokString, text: src[start:i], value: b.String(), pos: start}
+	case c == '-' || isDigit(c):
+		for i++; i < len(src) && isDigit(src[i]); i++ {
+		}
+		text := src[start:i]
+		n, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return p.errorAt(start, "invalid number '"+text+"'")
+		}
+		p.tok = token{kind: tokNumber, text: text, value: n, pos: start}
+	case isLetter(c):
+		for i++; i < len(src) && (isLetter(src[i]) || isDigit(src[i])); i++ {
+		}
+		p.tok = token{kind: tokIdent, text: src[start:i], pos: start}
+	default:
+		r, _ := utf8.DecodeRuneInString(src[i:])
+		return p.errorAt(start, fmt.Sprintf("unexpected character %q", r))
+	}
+
+	p.next = i
+	return nil
+}
+
+func isDigit(c byte) bool  { return c >= '0' && c <= '9' }
+func isLetter(c byte) bool { return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' }
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.keyword() == "or" {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = Or{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.tok.keyword() == "and" {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = And{left, right}
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	switch {
+	case p.tok.keyword() == "not":
+		if err := p.nest(); err != nil {
+			return nil, err
+		}
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		p.depth--
+		return Not{x}, nil
+
+	case p.tok.kind == tokLParen:
+		if err := p.nest(); err != nil {
+			return nil, err
+		}
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("unexpected %s, expected ')'", p.tok.describe())
+		}
+		p.depth--
+		return e, p.advance()
+	}
+
+	return p.parseComparison()
+}
+
+// nest enters a parenthesis or a not
+func (p *parser) nest() error {
+	p.depth++
+	if p.depth > MaxDepth {
+		return p.errorf("filter is nested more than %d levels deep", MaxDepth)
+	}
+	return p.advance()
+}
+
+func (p *parser) parseComparison() (Expr, error) {
+	if p.tok.kind != tokIdent || isReserved(p.tok.keyword()) {
+		return nil, p.errorf("unexpected %s, expected a field", p.tok.describe())
+	}
+
+	name := p.tok.text
+	field, ok := p.fields[name]
+	if !ok {
+		return nil, p.errorf("unknown field '%s', expected one of %s", name, strings.Join(p.fields.names(), ", "))
+	}
+	if field.Column == "" {
+		field.Column = name
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	op := Op(p.tok.keyword())
+	if !slices.Contains(ops, op) {
+		return nil, p.errorf("unexpected %s, expected an operator: %s", p.tok.describe(), opNames())
+	}
+	if (op == StartsWith || op == EndsWith || op == Contains) && field.Kind != String {
+		return nil, p.errorf("%s only applies to string fields, %s is a %s", op, name, field.Kind)
+	}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	c := Compare{Column: field.Column, Kind: field.Kind, Match: field.Match, Op: op}
+
+	if op != In {
+		value, err := p.parseValue(name, field, op)
+		if err != nil {
+			return nil, err
+		}
+		c.Values = []interface{}{value}
+		return c, nil
+	}
+
+	if p.tok.kind != tokLParen {
+		return nil, p.errorf("unexpected %s, expected '(' after in", p.tok.describe())
+	}
+	for {
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+		value, err := p.parseValue(name, field, op)
+		if err != nil {
+			return nil, err
+		}
+		c.Values = append(c.Values, value)
+
+		if p.tok.kind == tokRParen {
+			return c, p.advance()
+		}
+		if p.tok.kind != tokComma {
+			return nil, p.errorf("unexpected %s, expected ',' or ')'", p.tok.describe())
+		}
+	}
+}
+
+// parseValue reads a literal of the field's kind. Null is only compared
+// with eq and ne.
+func (p *parser) parseValue(name string, field Field, op Op) (interface{}, error) {
+	tok := p.tok
+
+	switch {
+	case tok.keyword() == "null":
+		if field.Kind != String || (op != Eq && op != Ne) {
+			return nil, p.errorf("null only applies to string fields with eq and ne")
+		}
+		return nil, p.advance()
+
+	case tok.kind == tokString && field.Kind == String,
+		tok.kind == tokNumber && field.Kind == Number:
+		return tok.value, p.advance()
+
+	case tok.kind == tokString || tok.kind == tokNumber:
+		return nil, p.errorf("%s is a %s field, %s isn't a %s", name, field.Kind, tok.describe(), field.Kind)
+	}
+
+	return nil, p.errorf("unexpected %s, expected a 'string', a number or null", tok.describe())
+}
+
+// isReserved reports whether an identifier is a keyword rather than a field
+func isReserved(keyword string) bool {
+	return keyword == "and" || keyword == "or" || keyword == "not" || keyword == "null"
+}
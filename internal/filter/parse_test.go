@@ -0,0 +1,139 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testFields = Fields{
+	"name":            {Kind: String},
+	"level":           {Kind: String},
+	"parent_code":     {Kind: String, Column: "prov_code", Match: RegionParent},
+	"income_class":    {Kind: String, Match: Footnoted},
+	"population_2020": {Kind: Number},
+}
+
+func eq(column string, value interface{}) Compare {
+	return Compare{Column: column, Kind: String, Op: Eq, Values: []interface{}{value}}
+}
+
+func TestParse(t *testing.T) {
+	a, b, c := eq("name", "a"), eq("name", "b"), eq("name", "c")
+
+	tests := []struct {
+		src  string
+		want Expr
+	}{
+		{"name eq 'a'", a},
+		{"name eq 'a' or name eq 'b' and name eq 'c'", Or{a, And{b, c}}},
+		{"name eq 'a' and name eq 'b' or name eq 'c'", Or{And{a, b}, c}},
+		{"name eq 'a' and (name eq 'b' or name eq 'c')", And{a, Or{b, c}}},
+		{"name eq 'a' or name eq 'b' or name eq 'c'", Or{Or{a, b}, c}},
+		{"not name eq 'a' and name eq 'b'", And{Not{a}, b}},
+		{"not (name eq 'a' and name eq 'b')", Not{And{a, b}}},
+		{"not not name eq 'a'", Not{Not{a}}},
+		{"((name eq 'a'))", a},
+		{"NAME EQ 'a'", nil}, // fields aren't keywords, their case counts
+		{"name EQ 'a' AND NOT name Eq 'b'", And{a, Not{b}}},
+		{"name eq 'It''s'", eq("name", "It's")},
+		{"name eq ''", eq("name", "")},
+		{"name eq null", eq("name", nil)},
+		{"name ne NULL", Compare{Column: "name", Kind: String, Op: Ne, Values: []interface{}{nil}}},
+		{"population_2020 ge -1", Compare{Column: "population_2020", Kind: Number, Op: Ge, Values: []interface{}{int64(-1)}}},
+		{"level in ('City', 'Mun')", Compare{Column: "level", Kind: String, Op: In, Values: []interface{}{"City", "Mun"}}},
+		{"population_2020 in (1,2)", Compare{Column: "population_2020", Kind: Number, Op: In, Values: []interface{}{int64(1), int64(2)}}},
+		{"name startswith 'San'", Compare{Column: "name", Kind: String, Op: StartsWith, Values: []interface{}{"San"}}},
+		{"parent_code eq '1300000000'", Compare{Column: "prov_code", Kind: String, Match: RegionParent, Op: Eq, Values: []interface{}{"1300000000"}}},
+		{"income_class ne '1st'", Compare{Column: "income_class", Kind: String, Match: Footnoted, Op: Ne, Values: []interface{}{"1st"}}},
+		{"\tname\neq 'a' ", a},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			got, err := Parse(tt.src, testFields)
+			if tt.want == nil {
+				if err == nil {
+					t.Fatalf("Parse() = %#v, want an error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+		msg string // the start of the message
+	}{
+		{"", 1, "filter is empty"},
+		{"   ", 1, "filter is empty"},
+		{"nme eq 'a'", 1, "unknown field 'nme', expected one of income_class, level, name, parent_code, population_2020"},
+		{"name is 'a'", 6, "unexpected 'is', expected an operator: eq, ne"},
+		{"name eq", 8, "unexpected end of filter, expected a 'string'"},
+		{"name eq 'a", 9, "unterminated string"},
+		{"name eq 'a' name eq 'b'", 13, "unexpected 'name', expected and, or or the end of filter"},
+		{"name eq 'a' and", 16, "unexpected end of filter, expected a field"},
+		{"name eq 'a' or or name eq 'b'", 16, "unexpected 'or', expected a field"},
+		{"(name eq 'a'", 13, "unexpected end of filter, expected ')'"},
+		{"name eq 'a')", 12, "unexpected ')', expected and, or"},
+		{"name eq 1", 9, "name is a string field, '1' isn't a string"},
+		{"population_2020 gt '1'", 20, "population_2020 is a number field, '1' isn't a number"},
+		{"population_2020 contains 1", 17, "contains only applies to string fields, population_2020 is a number"},
+		{"population_2020 eq null", 20, "null only applies to string fields with eq and ne"},
+		{"name startswith null", 17, "null only applies to string fields with eq and ne"},
+		{"name in ('a' 'b')", 14, "unexpected 'b', expected ',' or ')'"},
+		{"name in 'a'", 9, "unexpected 'a', expected '(' after in"},
+		{"name in ()", 10, "unexpected ')', expected a 'string'"},
+		{"population_2020 gt 99999999999999999999", 20, "invalid number"},
+		{"name eq 'a' & name eq 'b'", 13, "unexpected character '&'"},
+		// Positions count characters, not bytes
+		{"name eq 'Piñas' x", 17, "unexpected 'x'"},
+		{"not not", 8, "unexpected end of filter, expected a field"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := Parse(tt.src, testFields)
+
+			var ferr *Error
+			if !errors.As(err, &ferr) {
+				t.Fatalf("Parse() error = %v, want an *Error", err)
+			}
+			if ferr.Pos != tt.pos || !strings.HasPrefix(ferr.Msg, tt.msg) {
+				t.Errorf("Parse() error at %d %q, want at %d %q", ferr.Pos, ferr.Msg, tt.pos, tt.msg)
+			}
+		})
+	}
+}
+
+func TestParseLimits(t *testing.T) {
+	long := "name eq '" + strings.Repeat("a", MaxLength) + "'"
+	if _, err := Parse(long, testFields); err == nil || !strings.Contains(err.Error(), "longer than") {
+		t.Errorf("Parse(long) error = %v, want the filter too long", err)
+	}
+
+	nested := func(depth int) string {
+		return strings.Repeat("(", depth) + "name eq 'a'" + strings.Repeat(")", depth)
+	}
+	if _, err := Parse(nested(MaxDepth), testFields); err != nil {
+		t.Errorf("Parse(%d parentheses): %v", MaxDepth, err)
+	}
+	if _, err := Parse(nested(MaxDepth+1), testFields); err == nil || !strings.Contains(err.Error(), "nested more than") {
+		t.Errorf("Parse(%d parentheses) error = %v, want the filter too deep", MaxDepth+1, err)
+	}
+
+	nots := strings.Repeat("not ", MaxDepth+1) + "name eq 'a'"
+	if _, err := Parse(nots, testFields); err == nil || !strings.Contains(err.Error(), "nested more than") {
+		t.Errorf("Parse(%d nots) error = %v, want the filter too deep", MaxDepth+1, err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"database/sql"
 	"os"
+	"slices"
+	"strings"
 	"sync"
 	"sync/atomic"
 
@@ -23,6 +25,15 @@ const (
 	CsvFolder  = "files/csv"
 )
 
+// levelOrder is the order the geographic levels are loaded in, parents
+// before their children
+var levelOrder = [][]string{
+	{"Reg"},
+	{"Prov"},
+	{"City", "Mun", "SGU"},
+	{"Bgy"},
+}
+
 type Generator struct {
 	Filename string
 
@@ -30,6 +41,8 @@ type Generator struct {
 	provRepo     domain.ProvinceRepository
 	cityMuniRepo domain.CityMuniRepository
 	bgyRepo      domain.BarangayRepository
+	geoUnitRepo  domain.HierarchyRepository
+	statsRepo    domain.StatsRepository
 }
 
 func NewGenerator(Filename string, db *sql.DB) *Generator {
@@ -37,6 +50,8 @@ func NewGenerator(Filename string, db *sql.DB) *Generator {
 	provRepo := repository.NewDBProvince(db)
 	cityMuniRepo := repository.NewDBCityMuni(db)
 	brgyRepo := repository.NewDBBarangay(db)
+	geoUnitRepo := repository.NewDBHierarchy(db)
+	statsRepo := repository.NewDBStats(db)
 
 	return &Generator{
 		Filename: Filename,
@@ -45,6 +60,8 @@ func NewGenerator(Filename string, db *sql.DB) *Generator {
 		provRepo:     provRepo,
 		cityMuniRepo: cityMuniRepo,
 		bgyRepo:      brgyRepo,
+		geoUnitRepo:  geoUnitRepo,
+		statsRepo:    statsRepo,
 	}
 }
 
@@ -57,9 +74,57 @@ func (g *Generator) GenerateData(ctx context.Context, logger *zap.Logger) error
 
 	psgcData := []*domain.Masterlist{}
 
+	// Some masterlist headers span several lines, e.g. "Income\nClassification"
+	gocsv.SetHeaderNormalizer(func(header string) string {
+		return strings.Join(strings.Fields(header), " ")
+	})
+
 	if err := gocsv.Unmarshal(file, &psgcData); err != nil {
 		return err
 	}
+	resolveParents(psgcData)
+
+	// Counter to keep track of processed items
+	var processedCount int32
+
+	// Parents are loaded before their children, with foreign keys on a unit
+	// whose parent is missing fails the build instead of being orphaned.
+	for _, levels := range levelOrder {
+		records := []*domain.Masterlist{}
+		for _, data := range psgcData {
+			if slices.Contains(levels, data.Level) {
+				records = append(records, data)
+			}
+		}
+
+		count, err := g.createRecords(ctx, logger, records)
+		processedCount += count
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := g.geoUnitRepo.BuildClosure(ctx); err != nil {
+		return err
+	}
+	// The summary tables are computed from the closure
+	if err := g.statsRepo.Build(ctx); err != nil {
+		return err
+	}
+	// Log the total number of items processed
+	logger.Info("Total items processed", zap.Int32("Count", processedCount))
+
+	os.Exit(0)
+	return nil
+}
+
+// createRecords creates the records concurrently and returns how many were
+// created, with the first error encountered
+func (g *Generator) createRecords(
+	ctx context.Context,
+	logger *zap.Logger,
+	psgcData []*domain.Masterlist,
+) (int32, error) {
 	// Create a channel for errors during record creation
 	errCh := make(chan error, len(psgcData))
 
@@ -80,6 +145,8 @@ func (g *Generator) GenerateData(ctx context.Context, logger *zap.Logger) error
 					"Create error",
 					zap.Error(err),
 					zap.String("Level", data.Level),
+					zap.String("PsgcCode", data.PsgcCode),
+					zap.String("ParentCode", data.ParentCode),
 					zap.Int("Index", i),
 				)
 				errCh <- err
@@ -102,27 +169,72 @@ func (g *Generator) GenerateData(ctx context.Context, logger *zap.Logger) error
 	}
 
 	if len(errors) > 0 {
-		// You can decide how to handle errors here, e.g., return the first error encountered
-		return errors[0]
+		return processedCount, errors[0]
 	}
-	// Log the total number of items processed
-	logger.Info("Total items processed", zap.Int32("Count", processedCount))
 
-	os.Exit(0)
-	return nil
+	return processedCount, nil
+}
+
+// resolveParents sets the parent code of every record. The parent code is
+// the record's code cut to the parent level's digits:
+//   - provinces belong to the region in the first 2 digits
+//   - cities, municipalities and SGUs belong to the province in the first 5
+//     digits, independent cities and Pateros have none and get no parent
+//   - barangays belong to the city or municipality in the first 7 digits,
+//     the barangays of Manila's sub-municipalities to the City of Manila
+//
+// Codes are kept even when the parent is missing, the foreign keys reject
+// them when they are loaded.
+func resolveParents(psgcData []*domain.Masterlist) {
+	levels := make(map[string]string, len(psgcData))
+	for _, data := range psgcData {
+		levels[data.PsgcCode] = data.Level
+	}
+
+	truncate := func(psgcCode string, digits int) string {
+		return psgcCode[:digits] + strings.Repeat("0", len(psgcCode)-digits)
+	}
+
+	for _, data := range psgcData {
+		if len(data.PsgcCode) != 10 {
+			continue
+		}
+
+		switch data.Level {
+		case "Prov":
+			data.ParentCode = truncate(data.PsgcCode, 2)
+		case "City", "Mun", "SGU":
+			if provCode := truncate(data.PsgcCode, 5); levels[provCode] == "Prov" {
+				data.ParentCode = provCode
+			}
+		case "Bgy":
+			data.ParentCode = truncate(data.PsgcCode, 7)
+			if levels[data.ParentCode] == "SubMun" {
+				data.ParentCode = truncate(data.PsgcCode, 5)
+			}
+		}
+	}
 }
 
 func (g *Generator) createRecord(ctx context.Context, data *domain.Masterlist) error {
+	var err error
 	switch data.Level {
 	case "Reg":
-		return g.regRepo.Create(ctx, data)
+		err = g.regRepo.Create(ctx, data)
 	case "Prov":
-		return g.provRepo.Create(ctx, data)
-	case "City", "Mun":
-		return g.cityMuniRepo.Create(ctx, data)
+		err = g.provRepo.Create(ctx, data)
+	case "City", "Mun", "SGU":
+		err = g.cityMuniRepo.Create(ctx, data)
 	case "Bgy":
-		return g.bgyRepo.Create(ctx, data)
+		err = g.bgyRepo.Create(ctx, data)
 	default:
 		return nil
 	}
+
+	if err != nil {
+		return err
+	}
+
+	// Every loaded unit also goes into the unified hierarchy
+	return g.geoUnitRepo.Create(ctx, data)
 }
@@ -0,0 +1,104 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/util"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// maxBodySize bounds a POSTed request
+const maxBodySize = 1 << 20
+
+// Request is a GraphQL request, the JSON body of a POST or the query
+// parameters of a GET
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// ServeHTTP answers GraphQL requests over GET and POST. A request that can't
+// be read is a 400 problem, errors of the query itself are reported in the
+// errors of the result.
+func (s *Schema) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req Request
+
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		query := r.URL.Query()
+		req.Query = query.Get("query")
+		req.OperationName = query.Get("operationName")
+
+		if variables := query.Get("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+				util.ValidationError(w, r, domain.FieldError{
+					Field:      "variables",
+					Constraint: "json",
+					Message:    "variables should be a JSON object.",
+				})
+				return
+			}
+		}
+	case http.MethodPost:
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodySize)).Decode(&req); err != nil {
+			util.Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter,
+				"The body should be a JSON object with a query, an operationName and variables.")
+			return
+		}
+	default:
+		util.Error(w, r, http.StatusMethodNotAllowed, domain.CodeMethodNotAllowed,
+			"GraphQL requests are sent with GET or POST.")
+		return
+	}
+
+	if req.Query == "" {
+		util.ValidationError(w, r, domain.FieldError{
+			Field:      "query",
+			Constraint: "required",
+			Message:    "query is required.",
+		})
+		return
+	}
+
+	result := s.Do(r.Context(), req)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(result)
+}
+
+// Do parses, validates and runs a request. Operations over MaxDepth or
+// MaxComplexity are rejected before anything is fetched.
+func (s *Schema) Do(ctx context.Context, req Request) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(req.Query),
+			Name: "GraphQL request",
+		}),
+	})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	if validation := graphql.ValidateDocument(&s.schema, doc, nil); !validation.IsValid {
+		return &graphql.Result{Errors: validation.Errors}
+	}
+
+	if err := checkLimits(doc, req.OperationName, req.Variables, s.schema.QueryType()); err != nil {
+		return &graphql.Result{Errors: []gqlerrors.FormattedError{*err}}
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       context.WithValue(ctx, loadersCtx{}, s.newLoaders()),
+	})
+}
@@ -0,0 +1,208 @@
+package gql
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// MaxDepth is how deeply fields may be nested, regions { data {
+	// provinces { ... } } } is three levels
+	MaxDepth = 10
+	// MaxComplexity bounds the estimated number of fields a query resolves
+	MaxComplexity = 10000
+	// childListSize is the estimated length of a list of children, e.g. the
+	// barangays of a city/municipality, root lists count per_page instead
+	childListSize = 20
+)
+
+// limits estimates the cost of an operation before it runs. Every field
+// costs one, and the fields under a list count once per estimated item.
+type limits struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// checkLimits rejects an operation nested deeper than MaxDepth or more
+// complex than MaxComplexity. The document has already been validated, so
+// fragments don't form cycles.
+func checkLimits(
+	doc *ast.Document,
+	operationName string,
+	variables map[string]interface{},
+	query *graphql.Object,
+) *gqlerrors.FormattedError {
+	l := &limits{
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+	}
+
+	var operation *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.FragmentDefinition:
+			l.fragments[def.Name.Value] = def
+		case *ast.OperationDefinition:
+			if operationName == "" || (def.Name != nil && def.Name.Value == operationName) {
+				operation = def
+			}
+		}
+	}
+
+	// The executor reports a missing operation
+	if operation == nil {
+		return nil
+	}
+
+	complexity, err := l.cost(operation.SelectionSet, query, 0, 0)
+	if err != nil {
+		return err
+	}
+
+	if complexity > MaxComplexity {
+		return limitError(operation, "COMPLEXITY_LIMIT_EXCEEDED",
+			fmt.Sprintf("query complexity is over the limit of %d, lower per_page or select fewer nested lists", MaxComplexity))
+	}
+
+	return nil
+}
+
+// cost is the complexity of a selection set on obj at depth. pageSize is
+// the per_page of the field it belongs to, 0 outside root lists.
+func (l *limits) cost(
+	set *ast.SelectionSet,
+	obj *graphql.Object,
+	depth, pageSize int,
+) (int, *gqlerrors.FormattedError) {
+	total := 0
+
+	for _, selection := range set.Selections {
+		var (
+			n   int
+			err *gqlerrors.FormattedError
+		)
+
+		switch selection := selection.(type) {
+		case *ast.Field:
+			n, err = l.fieldCost(selection, obj, depth, pageSize)
+		case *ast.InlineFragment:
+			n, err = l.cost(selection.SelectionSet, obj, depth, pageSize)
+		case *ast.FragmentSpread:
+			if fragment, ok := l.fragments[selection.Name.Value]; ok {
+				n, err = l.cost(fragment.SelectionSet, obj, depth, pageSize)
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+
+		// Stop counting once over the limit, the product of nested lists
+		// could overflow
+		total += n
+		if total > MaxComplexity {
+			return total, nil
+		}
+	}
+
+	return total, nil
+}
+
+func (l *limits) fieldCost(
+	field *ast.Field,
+	obj *graphql.Object,
+	depth, pageSize int,
+) (int, *gqlerrors.FormattedError) {
+	// Introspection fields like __schema and __typename aren't counted
+	def, ok := obj.Fields()[field.Name.Value]
+	if !ok || field.SelectionSet == nil {
+		return 1, nil
+	}
+
+	if depth+1 > MaxDepth {
+		return 0, limitError(field, "DEPTH_LIMIT_EXCEEDED",
+			fmt.Sprintf("query is nested deeper than %d levels", MaxDepth))
+	}
+
+	child, isList := unwrap(def.Type)
+	if child == nil {
+		return 1, nil
+	}
+
+	cost, err := l.cost(field.SelectionSet, child, depth+1, l.perPage(field, def))
+	if err != nil {
+		return 0, err
+	}
+
+	if isList {
+		size := childListSize
+		if pageSize > 0 {
+			size = pageSize
+		}
+		cost *= size
+	}
+
+	return 1 + cost, nil
+}
+
+// perPage is the per_page of a root list, 0 for fields that aren't paged
+func (l *limits) perPage(field *ast.Field, def *graphql.FieldDefinition) int {
+	paged := false
+	for _, arg := range def.Args {
+		paged = paged || arg.Name() == "per_page"
+	}
+	if !paged {
+		return 0
+	}
+
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "per_page" {
+			continue
+		}
+
+		switch value := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(value.Value); err == nil {
+				return min(max(n, 1), MaxPerPage)
+			}
+		case *ast.Variable:
+			// Variables decoded from JSON are float64
+			switch n := l.variables[value.Name.Value].(type) {
+			case float64:
+				return min(max(int(n), 1), MaxPerPage)
+			case int:
+				return min(max(n, 1), MaxPerPage)
+			}
+		}
+	}
+
+	return DefaultPerPage
+}
+
+// unwrap returns the object type of a field, and whether it's a list of it
+func unwrap(t graphql.Type) (*graphql.Object, bool) {
+	isList := false
+	for {
+		switch wrapped := t.(type) {
+		case *graphql.NonNull:
+			t = wrapped.OfType
+		case *graphql.List:
+			isList = true
+			t = wrapped.OfType
+		case *graphql.Object:
+			return wrapped, isList
+		default:
+			return nil, isList
+		}
+	}
+}
+
+func limitError(node ast.Node, code, msg string) *gqlerrors.FormattedError {
+	err := gqlerrors.FormatError(gqlerrors.NewLocatedError(msg, []ast.Node{node}))
+	err.Extensions = map[string]interface{}{"code": code}
+
+	return &err
+}
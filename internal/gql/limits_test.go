@@ -0,0 +1,96 @@
+package gql
+
+import (
+	"strings"
+	"testing"
+)
+
+// deep nests province, region and provinces fields to depth levels
+func deep(depth int) string {
+	fields := []string{`province(psgc_code: "0102800000")`}
+	for len(fields) < depth {
+		if len(fields)%2 == 1 {
+			fields = append(fields, "region")
+		} else {
+			fields = append(fields, "provinces")
+		}
+	}
+
+	return "{ " + strings.Join(fields, " { ") + " { name" + strings.Repeat(" }", depth) + " }"
+}
+
+func TestCheckLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		// code is the code of the error, empty when the query runs
+		code string
+	}{
+		{"flat list", `{ barangays(per_page: 1000) { data { name } } }`, nil, ""},
+		{"nested lists", `{ provinces(per_page: 10) { data { cities_municipalities { barangays { name } } } } }`, nil, ""},
+		{"too many nested lists", `{ provinces(per_page: 1000) { data { cities_municipalities { barangays { name } } } } }`, nil, "COMPLEXITY_LIMIT_EXCEEDED"},
+		{"default per_page", `{ provinces { data { cities_municipalities { barangays { name } } } } }`, nil, "COMPLEXITY_LIMIT_EXCEEDED"},
+		{
+			"per_page variable",
+			`query($n: Int) { provinces(per_page: $n) { data { cities_municipalities { barangays { name } } } } }`,
+			map[string]interface{}{"n": float64(10)},
+			"",
+		},
+		{
+			"per_page variable over the limit",
+			`query($n: Int) { provinces(per_page: $n) { data { cities_municipalities { barangays { name } } } } }`,
+			map[string]interface{}{"n": float64(1000)},
+			"COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		{
+			"fragment",
+			`{ provinces(per_page: 1000) { data { ...children } } }
+			fragment children on Province { cities_municipalities { barangays { name } } }`,
+			nil,
+			"COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		{
+			"inline fragment",
+			`{ provinces(per_page: 1000) { data { ... on Province { cities_municipalities { barangays { name } } } } } }`,
+			nil,
+			"COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		// The lists of a query as deep as the limit are over the complexity
+		{"as deep as the limit", deep(MaxDepth), nil, "COMPLEXITY_LIMIT_EXCEEDED"},
+		{"too deep", deep(MaxDepth + 1), nil, "DEPTH_LIMIT_EXCEEDED"},
+		{
+			"too deep in fragments",
+			`{ barangay(psgc_code: "0102801001") { city_muni { ...cityMuni } } }
+			fragment cityMuni on CityMuni { province { ...province } }
+			fragment province on Province { region { provinces { region { provinces { ... on Province { region { provinces { region { provinces { name } } } } } } } } } }`,
+			nil,
+			"DEPTH_LIMIT_EXCEEDED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, q := newTestSchema(t)
+
+			_, result := do(t, s, tt.query, tt.variables)
+
+			if tt.code == "" {
+				if result.HasErrors() {
+					t.Fatalf("errors: %v", result.Errors)
+				}
+				return
+			}
+
+			if len(result.Errors) != 1 {
+				t.Fatalf("errors = %v, want %s", result.Errors, tt.code)
+			}
+			if code := result.Errors[0].Extensions["code"]; code != tt.code {
+				t.Errorf("error %q has code %v, want %s", result.Errors[0].Message, code, tt.code)
+			}
+			if result.Data != nil || len(q) > 0 {
+				t.Errorf("a rejected query ran: data %v, queries %v", result.Data, q)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package gql
+
+import (
+	"context"
+	"sync"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// loader batches lookups by key. load registers a key and returns a thunk,
+// the executor resolves every field of a level before running their thunks,
+// so the first thunk fetches the keys of the whole level in one query.
+type loader[T any] struct {
+	fetch func(ctx context.Context, keys []string) ([]T, error)
+	// key is the key an item is loaded under, its own code or its parent's
+	key func(item *T) string
+
+	mu      sync.Mutex
+	pending []string
+	queued  map[string]bool
+	results map[string][]T
+	errs    map[string]error
+}
+
+func newLoader[T any](
+	fetch func(ctx context.Context, keys []string) ([]T, error),
+	key func(item *T) string,
+) *loader[T] {
+	return &loader[T]{
+		fetch:   fetch,
+		key:     key,
+		queued:  map[string]bool{},
+		results: map[string][]T{},
+		errs:    map[string]error{},
+	}
+}
+
+// load returns a thunk of the items loaded under key
+func (l *loader[T]) load(ctx context.Context, key string) func() ([]T, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() ([]T, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) > 0 {
+			l.run(ctx)
+		}
+
+		return l.results[key], l.errs[key]
+	}
+}
+
+// run fetches the pending keys, l.mu is held
+func (l *loader[T]) run(ctx context.Context) {
+	keys := l.pending
+	l.pending = nil
+
+	items, err := l.fetch(ctx, keys)
+	if err != nil {
+		for _, key := range keys {
+			l.errs[key] = err
+		}
+		return
+	}
+
+	for i := range items {
+		key := l.key(&items[i])
+		l.results[key] = append(l.results[key], items[i])
+	}
+}
+
+// loaders are the loaders of one request
+type loaders struct {
+	region   *loader[domain.Region]
+	province *loader[domain.Province]
+	cityMuni *loader[domain.CityMuni]
+
+	provinces *loader[domain.Province]
+	// The cities/municipalities of regions and of provinces are loaded
+	// apart, the two levels are resolved at different times and a shared
+	// loader would split each into several batches
+	regionCitiesMunicipalities   *loader[domain.CityMuni]
+	provinceCitiesMunicipalities *loader[domain.CityMuni]
+	barangays                    *loader[domain.Barangay]
+}
+
+type loadersCtx struct{}
+
+func (s *Schema) newLoaders() *loaders {
+	return &loaders{
+		region: newLoader(s.regRepo.GetByIds,
+			func(r *domain.Region) string { return r.PsgcCode }),
+		province: newLoader(s.provRepo.GetByIds,
+			func(p *domain.Province) string { return p.PsgcCode }),
+		cityMuni: newLoader(s.cityMuniRepo.GetByIds,
+			func(c *domain.CityMuni) string { return c.PsgcCode }),
+
+		provinces: newLoader(s.provRepo.GetByParents,
+			func(p *domain.Province) string { return p.RegCode }),
+		regionCitiesMunicipalities:   newLoader(s.cityMuniRepo.GetByParents, (*domain.CityMuni).ParentCode),
+		provinceCitiesMunicipalities: newLoader(s.cityMuniRepo.GetByParents, (*domain.CityMuni).ParentCode),
+		barangays: newLoader(s.bgyRepo.GetByParents,
+			func(b *domain.Barangay) string { return b.CityMuniCode }),
+	}
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersCtx{}).(*loaders)
+}
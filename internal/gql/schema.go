@@ -0,0 +1,422 @@
+// Package gql serves the regions, provinces, cities/municipalities and
+// barangays as a GraphQL schema. Parents and children are loaded in batches,
+// one query per level of a response whatever the number of units in it.
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/graphql-go/graphql"
+	"go.uber.org/zap"
+)
+
+const (
+	DefaultPerPage = 100
+	MaxPerPage     = 1000
+)
+
+// Schema is the GraphQL schema over the geographic repositories
+type Schema struct {
+	logger *zap.Logger
+	schema graphql.Schema
+
+	regRepo      domain.RegionRepository
+	provRepo     domain.ProvinceRepository
+	cityMuniRepo domain.CityMuniRepository
+	bgyRepo      domain.BarangayRepository
+}
+
+func NewSchema(
+	logger *zap.Logger,
+	regRepo domain.RegionRepository,
+	provRepo domain.ProvinceRepository,
+	cityMuniRepo domain.CityMuniRepository,
+	bgyRepo domain.BarangayRepository,
+) (*Schema, error) {
+	s := &Schema{
+		logger:       logger,
+		regRepo:      regRepo,
+		provRepo:     provRepo,
+		cityMuniRepo: cityMuniRepo,
+		bgyRepo:      bgyRepo,
+	}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{Query: s.query()})
+	if err != nil {
+		return nil, err
+	}
+	s.schema = schema
+
+	return s, nil
+}
+
+func (s *Schema) query() *graphql.Object {
+	var region, province, cityMuni, barangay *graphql.Object
+
+	region = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Region",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := scalarFields(reflect.TypeOf(domain.Region{}))
+			fields["provinces"] = &graphql.Field{
+				Type: listOf(province),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := p.Source.(domain.Region)
+					return many(s, "failed to fetch provinces", loadersFrom(p.Context).provinces.load(p.Context, r.PsgcCode)), nil
+				},
+			}
+			fields["cities_municipalities"] = &graphql.Field{
+				Type:        listOf(cityMuni),
+				Description: "The cities and municipalities without a province, e.g. the NCR cities",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					r := p.Source.(domain.Region)
+					return many(s, "failed to fetch cities/municipalities", loadersFrom(p.Context).regionCitiesMunicipalities.load(p.Context, r.PsgcCode)), nil
+				},
+			}
+			return fields
+		}),
+	})
+
+	province = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Province",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := scalarFields(reflect.TypeOf(domain.Province{}))
+			fields["region"] = &graphql.Field{
+				Type: region,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					pr := p.Source.(domain.Province)
+					return one(s, "failed to fetch region", loadersFrom(p.Context).region.load(p.Context, pr.RegCode)), nil
+				},
+			}
+			fields["cities_municipalities"] = &graphql.Field{
+				Type: listOf(cityMuni),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					pr := p.Source.(domain.Province)
+					return many(s, "failed to fetch cities/municipalities", loadersFrom(p.Context).provinceCitiesMunicipalities.load(p.Context, pr.PsgcCode)), nil
+				},
+			}
+			fields["cities_municipalities_count"] = &graphql.Field{
+				Type:        graphql.NewNonNull(graphql.Int),
+				Description: "The number of cities and municipalities of the province",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					pr := p.Source.(domain.Province)
+					return count(s, "failed to fetch cities/municipalities", loadersFrom(p.Context).provinceCitiesMunicipalities.load(p.Context, pr.PsgcCode)), nil
+				},
+			}
+			return fields
+		}),
+	})
+
+	cityMuni = graphql.NewObject(graphql.ObjectConfig{
+		Name: "CityMuni",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := scalarFields(reflect.TypeOf(domain.CityMuni{}))
+			fields["region"] = &graphql.Field{
+				Type: region,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					c := p.Source.(domain.CityMuni)
+					return one(s, "failed to fetch region", loadersFrom(p.Context).region.load(p.Context, domain.RegionOf(c.PsgcCode))), nil
+				},
+			}
+			fields["province"] = &graphql.Field{
+				Type:        province,
+				Description: "null for independent cities, Pateros and SGUs",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					c := p.Source.(domain.CityMuni)
+					if c.ProvCode == "" {
+						return nil, nil
+					}
+					return one(s, "failed to fetch province", loadersFrom(p.Context).province.load(p.Context, c.ProvCode)), nil
+				},
+			}
+			fields["barangays"] = &graphql.Field{
+				Type: listOf(barangay),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					c := p.Source.(domain.CityMuni)
+					return many(s, "failed to fetch barangays", loadersFrom(p.Context).barangays.load(p.Context, c.PsgcCode)), nil
+				},
+			}
+			return fields
+		}),
+	})
+
+	barangay = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Barangay",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := scalarFields(reflect.TypeOf(domain.Barangay{}))
+			fields["region"] = &graphql.Field{
+				Type: region,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					b := p.Source.(domain.Barangay)
+					return one(s, "failed to fetch region", loadersFrom(p.Context).region.load(p.Context, domain.RegionOf(b.PsgcCode))), nil
+				},
+			}
+			fields["province"] = &graphql.Field{
+				Type:        province,
+				Description: "null for the barangays of independent cities, Pateros and SGUs",
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					b := p.Source.(domain.Barangay)
+					// A province's code is the first five digits of the codes
+					// nested in it, there's no province under an independent
+					// city's digits
+					return one(s, "failed to fetch province", loadersFrom(p.Context).province.load(p.Context, domain.ProvinceOf(b.PsgcCode))), nil
+				},
+			}
+			fields["city_muni"] = &graphql.Field{
+				Type: cityMuni,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					b := p.Source.(domain.Barangay)
+					return one(s, "failed to fetch city/municipality", loadersFrom(p.Context).cityMuni.load(p.Context, b.CityMuniCode)), nil
+				},
+			}
+			return fields
+		}),
+	})
+
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"region": byCode(s, "failed to fetch region", region, s.regRepo.GetById),
+			"regions": list(s, "failed to fetch regions", "Regions", region, domain.RegionSortFields, false,
+				func(ctx context.Context, params domain.PaginationParams) (interface{}, error) {
+					return s.regRepo.GetAll(ctx, params)
+				}),
+			"province": byCode(s, "failed to fetch province", province, s.provRepo.GetById),
+			"provinces": list(s, "failed to fetch provinces", "Provinces", province, domain.ProvinceSortFields, true,
+				func(ctx context.Context, params domain.PaginationParams) (interface{}, error) {
+					return s.provRepo.GetAll(ctx, params)
+				}),
+			"city_muni": byCode(s, "failed to fetch city/municipality", cityMuni, s.cityMuniRepo.GetById),
+			"cities_municipalities": list(s, "failed to fetch cities/municipalities", "CitiesMunicipalities", cityMuni, domain.CityMuniSortFields, true,
+				func(ctx context.Context, params domain.PaginationParams) (interface{}, error) {
+					return s.cityMuniRepo.GetAll(ctx, params)
+				}),
+			"barangay": byCode(s, "failed to fetch barangay", barangay, s.bgyRepo.GetById),
+			"barangays": list(s, "failed to fetch barangays", "Barangays", barangay, domain.BarangaySortFields, true,
+				func(ctx context.Context, params domain.PaginationParams) (interface{}, error) {
+					return s.bgyRepo.GetAll(ctx, params)
+				}),
+		},
+	})
+}
+
+var metaData = graphql.NewObject(graphql.ObjectConfig{
+	Name:   "MetaData",
+	Fields: scalarFields(reflect.TypeOf(domain.MetaData{})),
+})
+
+// byCode is a root field returning one unit, null for an unknown code
+func byCode[T any](
+	s *Schema,
+	msg string,
+	t *graphql.Object,
+	get func(ctx context.Context, psgcCode string) (T, error),
+) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Args: graphql.FieldConfigArgument{
+			"psgc_code": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			item, err := get(p.Context, p.Args["psgc_code"].(string))
+			if errors.Is(err, domain.ErrNotFound) {
+				return nil, nil
+			}
+
+			if err != nil {
+				return nil, s.fail(err, msg)
+			}
+
+			return item, nil
+		},
+	}
+}
+
+// list is a root field returning a page of units, with the parameters and
+// metadata of the REST lists. Lists of units with a parent take a parent
+// argument, the regions have none.
+func list(
+	s *Schema,
+	msg string,
+	name string,
+	t *graphql.Object,
+	sortFields []string,
+	parented bool,
+	getAll func(ctx context.Context, params domain.PaginationParams) (interface{}, error),
+) *graphql.Field {
+	page := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Paginated" + name,
+		Fields: graphql.Fields{
+			"metadata": &graphql.Field{
+				Type: graphql.NewNonNull(metaData),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reflect.ValueOf(p.Source).FieldByName("MetaData").Interface(), nil
+				},
+			},
+			"data": &graphql.Field{
+				Type: listOf(t),
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return reflect.ValueOf(p.Source).FieldByName("Data").Interface(), nil
+				},
+			},
+		},
+	})
+
+	args := graphql.FieldConfigArgument{
+		"page":     &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: 1},
+		"per_page": &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: DefaultPerPage},
+		"keyword":  &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: ""},
+		"sort":     &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "psgc_code"},
+		"order":    &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: domain.OrderAsc},
+		"cursor":   &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: ""},
+	}
+	if parented {
+		args["parent"] = &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: ""}
+	}
+
+	return &graphql.Field{
+		Type: graphql.NewNonNull(page),
+		Args: args,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			params := domain.PaginationParams{
+				Page:    p.Args["page"].(int),
+				PerPage: p.Args["per_page"].(int),
+				Keyword: p.Args["keyword"].(string),
+				Sort:    p.Args["sort"].(string),
+				Order:   strings.ToLower(p.Args["order"].(string)),
+				Cursor:  p.Args["cursor"].(string),
+			}
+			if parented {
+				params.Parent = p.Args["parent"].(string)
+			}
+
+			switch {
+			case params.Page < 1:
+				return nil, errors.New("page should be at least 1")
+			case params.PerPage < 1 || params.PerPage > MaxPerPage:
+				return nil, fmt.Errorf("per_page should be between 1 and %d", MaxPerPage)
+			case !slices.Contains(sortFields, params.Sort):
+				return nil, fmt.Errorf("sort should be one of %s", strings.Join(sortFields, ", "))
+			case params.Order != domain.OrderAsc && params.Order != domain.OrderDesc:
+				return nil, fmt.Errorf("order should be one of %s, %s", domain.OrderAsc, domain.OrderDesc)
+			case params.Parent != "" && !isPsgcCode(params.Parent):
+				return nil, errors.New("parent should be a psgc_code of 10 digits")
+			}
+
+			page, err := getAll(p.Context, params)
+			if err != nil {
+				return nil, s.fail(err, msg)
+			}
+
+			return page, nil
+		},
+	}
+}
+
+// isPsgcCode reports whether code is 10 digits, like the parent parameter of
+// the REST lists
+func isPsgcCode(code string) bool {
+	if len(code) != 10 {
+		return false
+	}
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func listOf(t graphql.Type) graphql.Output {
+	return graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(t)))
+}
+
+// scalarFields are the fields of a domain struct, named like its JSON
+func scalarFields(t reflect.Type) graphql.Fields {
+	fields := graphql.Fields{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+
+		var typ graphql.Output
+		switch sf.Type.Kind() {
+		case reflect.String:
+			typ = graphql.String
+		case reflect.Int:
+			typ = graphql.Int
+		case reflect.Bool:
+			typ = graphql.Boolean
+		default:
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		index := i
+		fields[name] = &graphql.Field{
+			Type: graphql.NewNonNull(typ),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return reflect.ValueOf(p.Source).Field(index).Interface(), nil
+			},
+		}
+	}
+
+	return fields
+}
+
+// one resolves a loaded unit, null if there's none
+func one[T any](s *Schema, msg string, thunk func() ([]T, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		items, err := thunk()
+		if err != nil {
+			return nil, s.fail(err, msg)
+		}
+		if len(items) == 0 {
+			return nil, nil
+		}
+
+		return items[0], nil
+	}
+}
+
+// many resolves loaded units
+func many[T any](s *Schema, msg string, thunk func() ([]T, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		items, err := thunk()
+		if err != nil {
+			return nil, s.fail(err, msg)
+		}
+		if items == nil {
+			items = []T{}
+		}
+
+		return items, nil
+	}
+}
+
+// count resolves the number of loaded units
+func count[T any](s *Schema, msg string, thunk func() ([]T, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		items, err := thunk()
+		if err != nil {
+			return nil, s.fail(err, msg)
+		}
+
+		return len(items), nil
+	}
+}
+
+// fail turns a repository error into the error of a field. Errors of the
+// server are logged and reported without their details, like the REST API.
+func (s *Schema) fail(err error, msg string) error {
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return err
+	}
+
+	s.logger.Error(msg, zap.Error(err))
+	return errors.New(msg)
+}
@@ -0,0 +1,220 @@
+package gql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/repository/memory"
+	"github.com/graphql-go/graphql"
+	"go.uber.org/zap"
+)
+
+// queries counts the batch lookups sent to the repositories
+type queries map[string]int
+
+type countingRegions struct {
+	domain.RegionRepository
+	queries queries
+}
+
+func (r countingRegions) GetByIds(ctx context.Context, psgcCodes []string) ([]domain.Region, error) {
+	r.queries["regions by code"]++
+	return r.RegionRepository.GetByIds(ctx, psgcCodes)
+}
+
+type countingProvinces struct {
+	domain.ProvinceRepository
+	queries queries
+}
+
+func (r countingProvinces) GetByIds(ctx context.Context, psgcCodes []string) ([]domain.Province, error) {
+	r.queries["provinces by code"]++
+	return r.ProvinceRepository.GetByIds(ctx, psgcCodes)
+}
+
+func (r countingProvinces) GetByParents(ctx context.Context, parentCodes []string) ([]domain.Province, error) {
+	r.queries["provinces by parent"]++
+	return r.ProvinceRepository.GetByParents(ctx, parentCodes)
+}
+
+type countingCitiesMunis struct {
+	domain.CityMuniRepository
+	queries queries
+}
+
+func (r countingCitiesMunis) GetByIds(ctx context.Context, psgcCodes []string) ([]domain.CityMuni, error) {
+	r.queries["cities/municipalities by code"]++
+	return r.CityMuniRepository.GetByIds(ctx, psgcCodes)
+}
+
+func (r countingCitiesMunis) GetByParents(ctx context.Context, parentCodes []string) ([]domain.CityMuni, error) {
+	r.queries["cities/municipalities by parent"]++
+	return r.CityMuniRepository.GetByParents(ctx, parentCodes)
+}
+
+type countingBarangays struct {
+	domain.BarangayRepository
+	queries queries
+}
+
+func (r countingBarangays) GetByParents(ctx context.Context, parentCodes []string) ([]domain.Barangay, error) {
+	r.queries["barangays by parent"]++
+	return r.BarangayRepository.GetByParents(ctx, parentCodes)
+}
+
+// newTestSchema serves two regions, one with two provinces and one with a
+// city outside of a province, from the in-memory repositories
+func newTestSchema(t *testing.T) (*Schema, queries) {
+	t.Helper()
+
+	q := queries{}
+	s, err := NewSchema(zap.NewNop(),
+		countingRegions{memory.NewRegion([]domain.Region{
+			{PsgcCode: "0100000000", Name: "Region I"},
+			{PsgcCode: "1300000000", Name: "NCR"},
+		}), q},
+		countingProvinces{memory.NewProvince([]domain.Province{
+			{PsgcCode: "0102800000", RegCode: "0100000000", Name: "Ilocos Norte"},
+			{PsgcCode: "0102900000", RegCode: "0100000000", Name: "Ilocos Sur"},
+		}), q},
+		countingCitiesMunis{memory.NewCityMuni([]domain.CityMuni{
+			{PsgcCode: "0102801000", ProvCode: "0102800000", Name: "Adams", Level: "Mun"},
+			{PsgcCode: "0102802000", ProvCode: "0102800000", Name: "Bacarra", Level: "Mun"},
+			{PsgcCode: "0102901000", ProvCode: "0102900000", Name: "Alilem", Level: "Mun"},
+			{PsgcCode: "1380100000", Name: "City of Caloocan", Level: "City"},
+		}), q},
+		countingBarangays{memory.NewBarangay([]domain.Barangay{
+			{PsgcCode: "0102801001", CityMuniCode: "0102801000", Name: "Adams"},
+			{PsgcCode: "0102802001", CityMuniCode: "0102802000", Name: "Bani"},
+			{PsgcCode: "0102802002", CityMuniCode: "0102802000", Name: "Buyon"},
+			{PsgcCode: "0102901001", CityMuniCode: "0102901000", Name: "Alilem Daya"},
+			{PsgcCode: "1380100001", CityMuniCode: "1380100000", Name: "Barangay 1"},
+		}), q},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, q
+}
+
+// do runs a query and returns its data as JSON
+func do(t *testing.T, s *Schema, query string, variables map[string]interface{}) (string, *graphql.Result) {
+	t.Helper()
+
+	result := s.Do(context.Background(), Request{Query: query, Variables: variables})
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data), result
+}
+
+func TestLoadersQueryOncePerLevel(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+		// queries are the batch lookups, one per level of the response
+		queries queries
+	}{
+		{
+			name: "children",
+			query: `{ regions(per_page: 2) { data { name
+				provinces { name cities_municipalities_count cities_municipalities { name } }
+				cities_municipalities { name barangays { name } }
+			} } }`,
+			want: `{"regions":{"data":[` +
+				`{"cities_municipalities":[],"name":"Region I","provinces":[` +
+				`{"cities_municipalities":[{"name":"Adams"},{"name":"Bacarra"}],"cities_municipalities_count":2,"name":"Ilocos Norte"},` +
+				`{"cities_municipalities":[{"name":"Alilem"}],"cities_municipalities_count":1,"name":"Ilocos Sur"}]},` +
+				`{"cities_municipalities":[{"barangays":[{"name":"Barangay 1"}],"name":"City of Caloocan"}],"name":"NCR","provinces":[]}]}}`,
+			queries: queries{
+				"provinces by parent": 1,
+				// The cities/municipalities of the regions and of the
+				// provinces are on different levels
+				"cities/municipalities by parent": 2,
+				"barangays by parent":             1,
+			},
+		},
+		{
+			name:  "grandchildren",
+			query: `{ provinces(per_page: 2) { data { name cities_municipalities { name barangays { name } } } } }`,
+			want: `{"provinces":{"data":[` +
+				`{"cities_municipalities":[{"barangays":[{"name":"Adams"}],"name":"Adams"},{"barangays":[{"name":"Bani"},{"name":"Buyon"}],"name":"Bacarra"}],"name":"Ilocos Norte"},` +
+				`{"cities_municipalities":[{"barangays":[{"name":"Alilem Daya"}],"name":"Alilem"}],"name":"Ilocos Sur"}]}}`,
+			queries: queries{
+				"cities/municipalities by parent": 1,
+				"barangays by parent":             1,
+			},
+		},
+		{
+			name:  "parents",
+			query: `{ barangays { data { name city_muni { name province { name region { name } } } } } }`,
+			want: `{"barangays":{"data":[` +
+				`{"city_muni":{"name":"Adams","province":{"name":"Ilocos Norte","region":{"name":"Region I"}}},"name":"Adams"},` +
+				`{"city_muni":{"name":"Bacarra","province":{"name":"Ilocos Norte","region":{"name":"Region I"}}},"name":"Bani"},` +
+				`{"city_muni":{"name":"Bacarra","province":{"name":"Ilocos Norte","region":{"name":"Region I"}}},"name":"Buyon"},` +
+				`{"city_muni":{"name":"Alilem","province":{"name":"Ilocos Sur","region":{"name":"Region I"}}},"name":"Alilem Daya"},` +
+				`{"city_muni":{"name":"City of Caloocan","province":null},"name":"Barangay 1"}]}}`,
+			queries: queries{
+				"cities/municipalities by code": 1,
+				"provinces by code":             1,
+				"regions by code":               1,
+			},
+		},
+		{
+			name:    "province count",
+			query:   `{ provinces { data { cities_municipalities_count } } }`,
+			want:    `{"provinces":{"data":[{"cities_municipalities_count":2},{"cities_municipalities_count":1}]}}`,
+			queries: queries{"cities/municipalities by parent": 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, q := newTestSchema(t)
+
+			got, result := do(t, s, tt.query, nil)
+			if result.HasErrors() {
+				t.Fatalf("errors: %v", result.Errors)
+			}
+			if got != tt.want {
+				t.Errorf("data = %s\nwant %s", got, tt.want)
+			}
+
+			gotQueries, _ := json.Marshal(q)
+			wantQueries, _ := json.Marshal(tt.queries)
+			if string(gotQueries) != string(wantQueries) {
+				t.Errorf("queries = %s, want %s", gotQueries, wantQueries)
+			}
+		})
+	}
+}
+
+func TestListParent(t *testing.T) {
+	s, _ := newTestSchema(t)
+
+	got, result := do(t, s, `{ provinces(parent: "0100000000") { data { name } } }`, nil)
+	if result.HasErrors() {
+		t.Fatalf("errors: %v", result.Errors)
+	}
+	if want := `{"provinces":{"data":[{"name":"Ilocos Norte"},{"name":"Ilocos Sur"}]}}`; got != want {
+		t.Errorf("data = %s, want %s", got, want)
+	}
+
+	for _, query := range []string{
+		`{ provinces(parent: "01") { data { name } } }`,
+		`{ cities_municipalities(parent: "01028000ab") { data { name } } }`,
+		`{ barangays(parent: "01028010000") { data { name } } }`,
+		// The regions have no parent
+		`{ regions(parent: "0100000000") { data { name } } }`,
+	} {
+		if _, result := do(t, s, query, nil); !result.HasErrors() {
+			t.Errorf("%s: no error", query)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package render
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// encoder writes one response in a format. A single item is written with
+// item, a list with begin, next for every item, then end.
+type encoder interface {
+	contentType() string
+	// download is the file extension of a format that is downloaded rather
+	// than shown, empty otherwise
+	download() string
+
+	item(v interface{}) error
+	// data writes a single item in the envelope of a list, without metadata
+	data(v interface{}) error
+
+	// begin starts a list of items of type t, metaData is nil for a list
+	// that isn't paginated
+	begin(metaData *domain.MetaData, t reflect.Type) error
+	next(v interface{}) error
+	end() error
+
+	flush() error
+}
+
+func newEncoder(w io.Writer, format Format) encoder {
+	bw := bufio.NewWriter(w)
+
+	switch format {
+	case CSV:
+		return &csvEncoder{w: bw, cw: csv.NewWriter(bw)}
+	case NDJSON:
+		return &ndjsonEncoder{w: bw, enc: json.NewEncoder(bw)}
+	case XML:
+		return &xmlEncoder{w: bw}
+	default:
+		return &jsonEncoder{w: bw}
+	}
+}
+
+// jsonEncoder keeps the shape the API has always had, a list is
+// {"metadata":{...},"data":[...]}
+type jsonEncoder struct {
+	w     *bufio.Writer
+	count int
+}
+
+func (e *jsonEncoder) contentType() string { return "application/json" }
+func (e *jsonEncoder) download() string    { return "" }
+
+func (e *jsonEncoder) item(v interface{}) error {
+	res, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = e.w.Write(res)
+	return err
+}
+
+func (e *jsonEncoder) data(v interface{}) error {
+	if _, err := e.w.WriteString(`{"data":`); err != nil {
+		return err
+	}
+	if err := e.item(v); err != nil {
+		return err
+	}
+
+	return e.w.WriteByte('}')
+}
+
+func (e *jsonEncoder) begin(metaData *domain.MetaData, _ reflect.Type) error {
+	if metaData == nil {
+		_, err := e.w.WriteString(`{"data":[`)
+		return err
+	}
+
+	if _, err := e.w.WriteString(`{"metadata":`); err != nil {
+		return err
+	}
+	if err := e.item(metaData); err != nil {
+		return err
+	}
+
+	_, err := e.w.WriteString(`,"data":[`)
+	return err
+}
+
+func (e *jsonEncoder) next(v interface{}) error {
+	if e.count > 0 {
+		if err := e.w.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	e.count++
+
+	return e.item(v)
+}
+
+func (e *jsonEncoder) end() error {
+	_, err := e.w.WriteString("]}")
+	return err
+}
+
+func (e *jsonEncoder) flush() error { return e.w.Flush() }
+
+// ndjsonEncoder writes one JSON object per line
+type ndjsonEncoder struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) contentType() string { return "application/x-ndjson" }
+func (e *ndjsonEncoder) download() string    { return "ndjson" }
+
+func (e *ndjsonEncoder) item(v interface{}) error                   { return e.enc.Encode(v) }
+func (e *ndjsonEncoder) data(v interface{}) error                   { return e.enc.Encode(v) }
+func (e *ndjsonEncoder) begin(*domain.MetaData, reflect.Type) error { return nil }
+func (e *ndjsonEncoder) next(v interface{}) error                   { return e.enc.Encode(v) }
+func (e *ndjsonEncoder) end() error                                 { return nil }
+func (e *ndjsonEncoder) flush() error                               { return e.w.Flush() }
+
+// csvEncoder writes a header row of the JSON field names, then a row per item
+type csvEncoder struct {
+	w  *bufio.Writer
+	cw *csv.Writer
+}
+
+func (e *csvEncoder) contentType() string { return "text/csv; charset=utf-8" }
+func (e *csvEncoder) download() string    { return "csv" }
+
+func (e *csvEncoder) item(v interface{}) error {
+	if err := e.begin(nil, reflect.TypeOf(v)); err != nil {
+		return err
+	}
+
+	return e.next(v)
+}
+
+func (e *csvEncoder) data(v interface{}) error { return e.item(v) }
+
+func (e *csvEncoder) begin(_ *domain.MetaData, t reflect.Type) error {
+	header := []string{}
+	for _, f := range fieldsOf(t) {
+		header = append(header, f.name)
+	}
+
+	return e.cw.Write(header)
+}
+
+func (e *csvEncoder) next(v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+
+	record := []string{}
+	for _, f := range fieldsOf(rv.Type()) {
+		record = append(record, fmt.Sprint(rv.Field(f.index).Interface()))
+	}
+
+	return e.cw.Write(record)
+}
+
+func (e *csvEncoder) end() error { return nil }
+
+func (e *csvEncoder) flush() error {
+	e.cw.Flush()
+	if err := e.cw.Error(); err != nil {
+		return err
+	}
+
+	return e.w.Flush()
+}
+
+// xmlEncoder names elements after the JSON fields, an item is <item>, a list
+// is <response> with <metadata> and <data>
+type xmlEncoder struct {
+	w *bufio.Writer
+}
+
+func (e *xmlEncoder) contentType() string { return "application/xml; charset=utf-8" }
+func (e *xmlEncoder) download() string    { return "" }
+
+func (e *xmlEncoder) item(v interface{}) error {
+	if _, err := e.w.WriteString(xml.Header); err != nil {
+		return err
+	}
+
+	return e.element("item", v)
+}
+
+func (e *xmlEncoder) data(v interface{}) error {
+	if _, err := e.w.WriteString(xml.Header + "<response>"); err != nil {
+		return err
+	}
+	if err := e.element("data", v); err != nil {
+		return err
+	}
+
+	_, err := e.w.WriteString("</response>")
+	return err
+}
+
+func (e *xmlEncoder) begin(metaData *domain.MetaData, _ reflect.Type) error {
+	if _, err := e.w.WriteString(xml.Header + "<response>"); err != nil {
+		return err
+	}
+
+	if metaData != nil {
+		if err := e.element("metadata", *metaData); err != nil {
+			return err
+		}
+	}
+
+	_, err := e.w.WriteString("<data>")
+	return err
+}
+
+func (e *xmlEncoder) next(v interface{}) error { return e.element("item", v) }
+
+func (e *xmlEncoder) end() error {
+	_, err := e.w.WriteString("</data></response>")
+	return err
+}
+
+func (e *xmlEncoder) flush() error { return e.w.Flush() }
+
+func (e *xmlEncoder) element(name string, v interface{}) error {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+
+	if _, err := e.w.WriteString("<" + name + ">"); err != nil {
+		return err
+	}
+	for _, f := range fieldsOf(rv.Type()) {
+		if _, err := e.w.WriteString("<" + f.name + ">"); err != nil {
+			return err
+		}
+		if err := xml.EscapeText(e.w, []byte(fmt.Sprint(rv.Field(f.index).Interface()))); err != nil {
+			return err
+		}
+		if _, err := e.w.WriteString("</" + f.name + ">"); err != nil {
+			return err
+		}
+	}
+	_, err := e.w.WriteString("</" + name + ">")
+
+	return err
+}
+
+type field struct {
+	name  string
+	index int
+}
+
+// fieldsOf returns the fields of a struct the way encoding/json names them,
+// none for any other type
+func fieldsOf(t reflect.Type) []field {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := []field{}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+
+		fields = append(fields, field{name: name, index: i})
+	}
+
+	return fields
+}
+
+// flat reports whether t is a struct, or a pointer to one, whose fields are
+// all scalars, the only shape a CSV row or the XML elements can hold
+func flat(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for _, f := range fieldsOf(t) {
+		switch t.Field(f.index).Type.Kind() {
+		case reflect.Bool, reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			return false
+		}
+	}
+
+	return true
+}
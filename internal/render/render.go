@@ -0,0 +1,272 @@
+// Package render writes API responses in the format a client asks for, by
+// the ?format parameter or the Accept header
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/util"
+)
+
+type (
+	FormatCtx struct{}
+	Format    string
+)
+
+const (
+	JSON   Format = "json"
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+	XML    Format = "xml"
+)
+
+// Formats are the supported formats, JSON first as the default
+var Formats = []Format{JSON, CSV, NDJSON, XML}
+
+// mediaTypes maps the media types of an Accept header to a format
+var mediaTypes = map[string]Format{
+	"application/json":     JSON,
+	"text/csv":             CSV,
+	"application/x-ndjson": NDJSON,
+	"application/ndjson":   NDJSON,
+	"application/xml":      XML,
+	"text/xml":             XML,
+}
+
+// Negotiate is a middleware that picks the response format, ?format takes
+// precedence over the Accept header. An unknown format is a 400, an Accept
+// header without any supported media type is a 406.
+func Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format, err := negotiate(r)
+		if err != nil {
+			if r.URL.Query().Has("format") {
+				util.ValidationError(w, r, domain.FieldError{
+					Field:      "format",
+					Constraint: "oneof=json csv ndjson xml",
+					Message:    err.Error(),
+				})
+				return
+			}
+
+			util.Error(w, r, http.StatusNotAcceptable, domain.CodeNotAcceptable, err.Error())
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), FormatCtx{}, format)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+func negotiate(r *http.Request) (Format, error) {
+	if s := r.URL.Query().Get("format"); s != "" {
+		for _, format := range Formats {
+			if Format(strings.ToLower(s)) == format {
+				return format, nil
+			}
+		}
+
+		return "", errors.New("format should be one of json, csv, ndjson, xml.")
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return JSON, nil
+	}
+
+	type candidate struct {
+		format Format
+		q      float64
+	}
+
+	candidates := []candidate{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(s, 64); err != nil || q <= 0 {
+				continue
+			}
+		}
+
+		if format, ok := mediaTypes[mediaType]; ok {
+			candidates = append(candidates, candidate{format, q})
+		} else if mediaType == "*/*" || mediaType == "application/*" || mediaType == "text/*" {
+			// Wildcards rank below an explicit media type of the same quality
+			candidates = append(candidates, candidate{JSON, q - 0.0001})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return "", errors.New("accept should include one of application/json, text/csv, application/x-ndjson, application/xml.")
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	return candidates[0].format, nil
+}
+
+// FromContext returns the format picked by Negotiate, JSON if it didn't run
+func FromContext(ctx context.Context) Format {
+	if format, ok := ctx.Value(FormatCtx{}).(Format); ok {
+		return format
+	}
+
+	return JSON
+}
+
+// Item writes a single item, name is the file name of a download
+func Item(w http.ResponseWriter, r *http.Request, item interface{}, name string) error {
+	format := FromContext(r.Context())
+	if !representable(w, r, format, reflect.TypeOf(item)) {
+		return nil
+	}
+
+	enc := newEncoder(w, format)
+	setHeaders(w, enc, name)
+
+	if err := enc.item(item); err != nil {
+		return err
+	}
+
+	return enc.flush()
+}
+
+// Data writes a single item in the envelope of a list, {"data":{...}} in
+// JSON and <response><data> in XML
+func Data(w http.ResponseWriter, r *http.Request, item interface{}, name string) error {
+	format := FromContext(r.Context())
+	if !representable(w, r, format, reflect.TypeOf(item)) {
+		return nil
+	}
+
+	enc := newEncoder(w, format)
+	setHeaders(w, enc, name)
+
+	if err := enc.data(item); err != nil {
+		return err
+	}
+
+	return enc.flush()
+}
+
+// List writes a page of items with its metadata, name is the file name of a
+// download. JSON and XML put the metadata in the body next to the items, CSV
+// and NDJSON only have rows so the metadata goes in X-* headers.
+func List[T any](
+	w http.ResponseWriter,
+	r *http.Request,
+	metaData domain.MetaData,
+	items []T,
+	name string,
+) error {
+	format := FromContext(r.Context())
+	if !representable(w, r, format, reflect.TypeOf((*T)(nil)).Elem()) {
+		return nil
+	}
+
+	enc := newEncoder(w, format)
+	setHeaders(w, enc, name)
+	setMetaDataHeaders(w, metaData)
+
+	if err := enc.begin(&metaData, reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := enc.next(item); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.end(); err != nil {
+		return err
+	}
+
+	return enc.flush()
+}
+
+// All writes a whole list that is short enough to hold, like Stream it has
+// no metadata, but unlike a stream it is kept in the response cache
+func All[T any](w http.ResponseWriter, r *http.Request, items []T, name string) error {
+	format := FromContext(r.Context())
+	if !representable(w, r, format, reflect.TypeOf((*T)(nil)).Elem()) {
+		return nil
+	}
+
+	enc := newEncoder(w, format)
+	setHeaders(w, enc, name)
+
+	if err := enc.begin(nil, reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := enc.next(item); err != nil {
+			return err
+		}
+	}
+
+	if err := enc.end(); err != nil {
+		return err
+	}
+
+	return enc.flush()
+}
+
+// representable answers a 406 when the format can't represent values of type
+// t, CSV and XML only write flat records
+func representable(w http.ResponseWriter, r *http.Request, format Format, t reflect.Type) bool {
+	if (format != CSV && format != XML) || flat(t) {
+		return true
+	}
+
+	util.Error(w, r, http.StatusNotAcceptable, domain.CodeNotAcceptable,
+		fmt.Sprintf("This response can't be written as %s, ask for json or ndjson.", format))
+	return false
+}
+
+func setHeaders(w http.ResponseWriter, enc encoder, name string) {
+	w.Header().Set("Content-Type", enc.contentType())
+	w.Header().Add("Vary", "Accept")
+
+	if ext := enc.download(); ext != "" {
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, name, ext))
+	}
+}
+
+// MetaDataHeaders are the headers a list's metadata is sent in
+var MetaDataHeaders = []string{
+	"X-Page",
+	"X-Per-Page",
+	"X-Total-Pages",
+	"X-Total-Items",
+	"X-Next-Cursor",
+	"X-Prev-Cursor",
+}
+
+func setMetaDataHeaders(w http.ResponseWriter, metaData domain.MetaData) {
+	w.Header().Set("X-Page", strconv.Itoa(metaData.Page))
+	w.Header().Set("X-Per-Page", strconv.Itoa(metaData.PerPage))
+	w.Header().Set("X-Total-Pages", strconv.Itoa(metaData.TotalPages))
+	w.Header().Set("X-Total-Items", strconv.Itoa(metaData.TotalItems))
+
+	if metaData.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", metaData.NextCursor)
+	}
+	if metaData.PrevCursor != "" {
+		w.Header().Set("X-Prev-Cursor", metaData.PrevCursor)
+	}
+}
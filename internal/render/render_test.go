@@ -0,0 +1,57 @@
+package render
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+func TestItemShapes(t *testing.T) {
+	type nested struct {
+		Name  string       `json:"name"`
+		Stats domain.Stats `json:"stats"`
+	}
+
+	region := domain.Region{PsgcCode: "0100000000", Name: "Region I"}
+	options := []domain.Option{{Value: "0100000000", Label: "Region I"}}
+
+	tests := []struct {
+		name   string
+		item   interface{}
+		format Format
+		want   int
+		body   string // a part of the body of a 200
+	}{
+		{"struct as csv", region, CSV, http.StatusOK, "psgc_code,name"},
+		{"pointer as xml", &region, XML, http.StatusOK, "<psgc_code>0100000000</psgc_code>"},
+		{"slice as json", options, JSON, http.StatusOK, `[{"value":"0100000000"`},
+		{"slice as csv", options, CSV, http.StatusNotAcceptable, ""},
+		{"slice as xml", options, XML, http.StatusNotAcceptable, ""},
+		{"map as csv", map[string]int{"regions": 17}, CSV, http.StatusNotAcceptable, ""},
+		{"nested struct as xml", nested{Name: "Region I"}, XML, http.StatusNotAcceptable, ""},
+		{"nested struct as ndjson", nested{Name: "Region I"}, NDJSON, http.StatusOK, `"stats":{`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/api/test", nil)
+			r = r.WithContext(context.WithValue(r.Context(), FormatCtx{}, tt.format))
+			w := httptest.NewRecorder()
+
+			if err := Item(w, r, tt.item, "test"); err != nil {
+				t.Fatalf("Item: %v", err)
+			}
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.want, w.Body)
+			}
+			if !strings.Contains(w.Body.String(), tt.body) {
+				t.Errorf("body %q doesn't contain %q", w.Body, tt.body)
+			}
+		})
+	}
+}
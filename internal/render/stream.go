@@ -0,0 +1,117 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/Brix101/psgc-tool/internal/cache"
+	"github.com/Brix101/psgc-tool/internal/util"
+)
+
+// FlushEvery is how many items Stream writes between flushes
+const FlushEvery = 500
+
+// ErrStreamStarted is wrapped in the error of a stream that failed after the
+// response started, too late to answer with a problem
+var ErrStreamStarted = errors.New("stream already started")
+
+// Stream writes every item each yields as it is read, without collecting
+// the list first. The response is flushed every FlushEvery items so the
+// client starts receiving rows right away, and each is stopped once the
+// client disconnects, which isn't reported as an error.
+//
+// A streamed list isn't paginated, JSON and XML write the data without the
+// metadata.
+//
+// An error before any of the response was sent leaves the response to the
+// caller, an error after it wraps ErrStreamStarted.
+func Stream[T any](
+	w http.ResponseWriter,
+	r *http.Request,
+	name string,
+	each func(fn func(item T) error) error,
+) error {
+	ctx := r.Context()
+
+	format := FromContext(ctx)
+	if !representable(w, r, format, reflect.TypeOf((*T)(nil)).Elem()) {
+		return nil
+	}
+
+	// A whole list is too large to keep
+	cache.Skip(r)
+
+	sw := util.NewStartedWriter(w)
+	rc := http.NewResponseController(sw)
+
+	enc := newEncoder(sw, format)
+	setHeaders(w, enc, name)
+
+	if err := stream(ctx, enc, rc, each); err != nil {
+		if sw.Started() {
+			return fmt.Errorf("%w: %w", ErrStreamStarted, err)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// stream writes the items each yields through enc, flushing as it goes
+func stream[T any](
+	ctx context.Context,
+	enc encoder,
+	rc *http.ResponseController,
+	each func(fn func(item T) error) error,
+) error {
+	if err := enc.begin(nil, reflect.TypeOf((*T)(nil)).Elem()); err != nil {
+		return err
+	}
+
+	count := 0
+	err := each(func(item T) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := enc.next(item); err != nil {
+			return err
+		}
+
+		count++
+		if count%FlushEvery == 0 {
+			return flush(enc, rc)
+		}
+
+		return nil
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := enc.end(); err != nil {
+		return err
+	}
+
+	return flush(enc, rc)
+}
+
+// flush sends the encoder's buffer to the client. A ResponseWriter that
+// can't flush still gets the data, only later.
+func flush(enc encoder, rc *http.ResponseController) error {
+	if err := enc.flush(); err != nil {
+		return err
+	}
+
+	if err := rc.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		return err
+	}
+
+	return nil
+}
@@ -3,231 +3,171 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"strings"
 
 	"github.com/Brix101/psgc-tool/internal/domain"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/trace"
 )
 
+var cityMuniColumns = []Column[domain.CityMuni]{
+	{"psgc_code", func(c *domain.CityMuni) interface{} { return &c.PsgcCode }},
+	{"prov_code", func(c *domain.CityMuni) interface{} { return nullString{&c.ProvCode} }},
+	{"name", func(c *domain.CityMuni) interface{} { return &c.Name }},
+	{"level", func(c *domain.CityMuni) interface{} { return &c.Level }},
+	{"city_class", func(c *domain.CityMuni) interface{} { return &c.CityClass }},
+	{"income_class", func(c *domain.CityMuni) interface{} { return &c.IncomeClass }},
+	{"status", func(c *domain.CityMuni) interface{} { return &c.Status }},
+	{"population_2015", func(c *domain.CityMuni) interface{} { return &c.Population2015 }},
+	{"population_2020", func(c *domain.CityMuni) interface{} { return &c.Population2020 }},
+}
+
 type dbCityMuniRepository struct {
-	conn   Connection
-	tracer trace.Tracer
+	table *Table[domain.CityMuni]
 }
 
 func NewDBCityMuni(conn *sql.DB) domain.CityMuniRepository {
-	tracer := otel.Tracer("db:sqlite3:cityMuni")
+	table := NewTable(conn, "city_muni", cityMuniColumns, domain.CityMuniSortFields)
 
-	return &dbCityMuniRepository{conn: conn, tracer: tracer}
+	return &dbCityMuniRepository{table: table}
 }
 
-func (p *dbCityMuniRepository) fetch(
-	ctx context.Context,
-	query string,
-	args ...interface{},
-) ([]domain.CityMuni, error) {
-	ctx, span := spanWithQuery(ctx, p.tracer, query)
-	defer span.End()
-
-	rows, err := p.conn.QueryContext(ctx, query, args...)
-	if err != nil {
-		span.SetStatus(codes.Error, "failed querying cityMuni")
-		span.RecordError(err)
-		return nil, err
+// parentFilter limits a list to the cities and municipalities of a
+// province. Independent cities, Pateros and SGUs have no province, they are
+// the children of their region instead.
+func (p *dbCityMuniRepository) parentFilter(parentCode string) []Filter {
+	if parentCode == "" {
+		return nil
 	}
-	defer rows.Close()
-
-	var mLst []domain.CityMuni
-	for rows.Next() {
-		var lst domain.CityMuni
-		if err := rows.Scan(
-			&lst.PsgcCode,
-			&lst.ProvCode,
-			&lst.Name,
-			&lst.Level,
-		); err != nil {
-			return nil, err
-		}
-		mLst = append(mLst, lst)
+
+	if strings.HasSuffix(parentCode, "00000000") {
+		return []Filter{{
+			Condition: "prov_code IS NULL AND psgc_code LIKE ? || '%'",
+			Args:      []interface{}{parentCode[:2]},
+		}}
 	}
-	return mLst, nil
+
+	return ParentFilter("prov_code", parentCode)
 }
 
-func (p *dbCityMuniRepository) paginatedQuery(
+// listFilters limits a list to the parent and the attribute values of params
+func (p *dbCityMuniRepository) listFilters(params domain.PaginationParams) []Filter {
+	return append(p.parentFilter(params.Parent), AttributeFilters(params, domain.CityMuniAttributes)...)
+}
+
+func (p *dbCityMuniRepository) paginate(
 	ctx context.Context,
-	level string,
 	params domain.PaginationParams,
+	filters ...Filter,
 ) (domain.PaginatedCityMuni, error) {
-	queryParams := []interface{}{}
-	query := `SELECT * FROM city_muni`
-	countQuery := `SELECT COUNT(*) FROM city_muni`
-
-	if level != "" {
-		query += fmt.Sprintf(" WHERE level = '%s' AND", level)
-		countQuery += fmt.Sprintf(" WHERE level = '%s'", level)
-	} else {
-		query += " WHERE "
-	}
-
-	if params.Keyword != "" {
-		query += `
-			(
-                LOWER(psgc_code) LIKE '%' || LOWER($1) || '%' OR
-                LOWER(name) LIKE '%' || LOWER($1) || '%' 
-            )
-        `
-		queryParams = append(queryParams, params.Keyword)
-	}
-
-	// Add sorting by name in ascending order.
-	query += `
-        ORDER BY name ASC
-        LIMIT $2
-        OFFSET $3
-    `
+	filters = append(filters, p.listFilters(params)...)
 
-	queryParams = append(queryParams, params.PerPage, (params.Page-1)*params.PerPage)
-
-	// Execute the query with appropriate parameters.
-	lst, err := p.fetch(ctx, query, queryParams...)
+	lst, metaData, err := p.table.Paginate(ctx, params, filters...)
 	if err != nil {
 		return domain.PaginatedCityMuni{}, err
 	}
 
-	totalItems := 0
-	if err := p.conn.QueryRowContext(ctx, countQuery).Scan(&totalItems); err != nil {
-		return domain.PaginatedCityMuni{}, err
-	}
-
-	totalPages := (totalItems + params.PerPage - 1) / params.PerPage
-
-	if len(lst) == 0 {
-		lst = []domain.CityMuni{}
-	}
-
-	metaData := domain.MetaData{
-		Page:       params.Page,
-		TotalPages: totalPages,
-		PerPage:    params.PerPage,
-		TotalItems: totalItems,
-		ItemCount:  len(lst),
-	}
-
-	res := domain.PaginatedCityMuni{
-		MetaData: metaData,
-		Data:     lst,
-	}
-
-	return res, nil
+	return domain.PaginatedCityMuni{MetaData: metaData, Data: lst}, nil
 }
 
 func (p *dbCityMuniRepository) GetAll(
 	ctx context.Context,
 	params domain.PaginationParams,
 ) (domain.PaginatedCityMuni, error) {
-	res, err := p.paginatedQuery(ctx, "", params)
-
-	return res, err
+	return p.paginate(ctx, params)
 }
 
 func (p *dbCityMuniRepository) GetById(
 	ctx context.Context,
 	psgcCode string,
 ) (domain.CityMuni, error) {
-	query := `SELECT * FROM city_muni WHERE psgc_code = $1`
+	return p.table.GetById(ctx, psgcCode)
+}
 
-	accs, err := p.fetch(ctx, query, psgcCode)
-	if err != nil {
-		return domain.CityMuni{}, err
-	}
+func (p *dbCityMuniRepository) GetByIds(
+	ctx context.Context,
+	psgcCodes []string,
+) ([]domain.CityMuni, error) {
+	return p.table.All(ctx, In("psgc_code", psgcCodes))
+}
 
-	if len(accs) == 0 {
-		return domain.CityMuni{}, domain.ErrNotFound
+func (p *dbCityMuniRepository) GetByParents(
+	ctx context.Context,
+	parentCodes []string,
+) ([]domain.CityMuni, error) {
+	filters := []Filter{}
+	for _, parentCode := range parentCodes {
+		filters = append(filters, p.parentFilter(parentCode)...)
 	}
-	return accs[0], nil
+
+	return p.table.All(ctx, Or(filters...))
+}
+
+func (p *dbCityMuniRepository) Each(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.CityMuni) error,
+) error {
+	return p.table.Each(ctx, params, fn, p.listFilters(params)...)
 }
 
 func (p *dbCityMuniRepository) GetAllCity(
 	ctx context.Context,
 	params domain.PaginationParams,
 ) (domain.PaginatedCityMuni, error) {
-	res, err := p.paginatedQuery(ctx, "City", params)
+	return p.paginate(ctx, params, Eq("level", "City"))
+}
 
-	return res, err
+func (p *dbCityMuniRepository) EachCity(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.CityMuni) error,
+) error {
+	filters := append(p.listFilters(params), Eq("level", "City"))
+	return p.table.Each(ctx, params, fn, filters...)
 }
 
 func (p *dbCityMuniRepository) GetCityById(
 	ctx context.Context,
 	psgcCode string,
 ) (domain.CityMuni, error) {
-	query := `SELECT * FROM city_muni WHERE level = 'City' AND psgc_code = $1`
-
-	accs, err := p.fetch(ctx, query, psgcCode)
-	if err != nil {
-		return domain.CityMuni{}, err
-	}
-
-	if len(accs) == 0 {
-		return domain.CityMuni{}, domain.ErrNotFound
-	}
-	return accs[0], nil
+	return p.table.Get(ctx, Eq("level", "City"), Eq("psgc_code", psgcCode))
 }
 
 func (p *dbCityMuniRepository) GetAllMunicipality(
 	ctx context.Context,
 	params domain.PaginationParams,
 ) (domain.PaginatedCityMuni, error) {
-	res, err := p.paginatedQuery(ctx, "Mun", params)
+	return p.paginate(ctx, params, Eq("level", "Mun"))
+}
 
-	return res, err
+func (p *dbCityMuniRepository) EachMunicipality(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.CityMuni) error,
+) error {
+	filters := append(p.listFilters(params), Eq("level", "Mun"))
+	return p.table.Each(ctx, params, fn, filters...)
 }
 
 func (p *dbCityMuniRepository) GetMunicipalityById(
 	ctx context.Context,
 	psgcCode string,
 ) (domain.CityMuni, error) {
-	query := `SELECT * FROM city_muni WHERE level = 'Mun' AND psgc_code = $1`
-
-	accs, err := p.fetch(ctx, query, psgcCode)
-	if err != nil {
-		return domain.CityMuni{}, err
-	}
-
-	if len(accs) == 0 {
-		return domain.CityMuni{}, domain.ErrNotFound
-	}
-	return accs[0], nil
+	return p.table.Get(ctx, Eq("level", "Mun"), Eq("psgc_code", psgcCode))
 }
 
 func (p *dbCityMuniRepository) Create(
 	ctx context.Context,
 	data *domain.Masterlist,
 ) error {
-	query := `
-		INSERT OR REPLACE INTO city_muni (psgc_code, name, level, prov_code)
-		VALUES (?, ?, ?, ?);`
-
-	ctx, span := spanWithQuery(ctx, p.tracer, query)
-	defer span.End()
-
-	psgcCode := data.PsgcCode
-	provCode := psgcCode[:5] + strings.Repeat("0", len(psgcCode)-5)
-
-	_, err := p.conn.ExecContext(
-		ctx,
-		query,
-		data.PsgcCode,
-		data.Name,
-		data.Level,
-		provCode,
-	)
-	if err != nil {
-		span.SetStatus(codes.Error, "failed inserting cityMuni")
-		span.RecordError(err)
-		return err
-	}
-
-	return nil
+	return p.table.Insert(ctx, domain.CityMuni{
+		PsgcCode:       data.PsgcCode,
+		ProvCode:       data.ParentCode,
+		Name:           data.Name,
+		Level:          data.Level,
+		CityClass:      data.CityClass,
+		IncomeClass:    data.IncomeClass,
+		Status:         data.Status,
+		Population2015: int(data.Population2015),
+		Population2020: int(data.Population2020),
+	})
 }
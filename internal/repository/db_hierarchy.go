@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var geoUnitColumns = []Column[domain.GeoUnit]{
+	{"psgc_code", func(g *domain.GeoUnit) interface{} { return &g.PsgcCode }},
+	{"name", func(g *domain.GeoUnit) interface{} { return &g.Name }},
+	{"level", func(g *domain.GeoUnit) interface{} { return &g.Level }},
+	{"parent_code", func(g *domain.GeoUnit) interface{} { return nullString{&g.ParentCode} }},
+	{"population_2015", func(g *domain.GeoUnit) interface{} { return &g.Population2015 }},
+	{"population_2020", func(g *domain.GeoUnit) interface{} { return &g.Population2020 }},
+}
+
+type dbHierarchyRepository struct {
+	table *Table[domain.GeoUnit]
+}
+
+func NewDBHierarchy(conn *sql.DB) domain.HierarchyRepository {
+	table := NewTable(conn, "geo_unit", geoUnitColumns, domain.GeoUnitSortFields)
+
+	return &dbHierarchyRepository{table: table}
+}
+
+// descendantOf filters the units below psgcCode through the closure table
+func descendantOf(psgcCode string) Filter {
+	return Filter{
+		Condition: `psgc_code IN (
+			SELECT descendant FROM geo_unit_closure WHERE ancestor = ? AND depth > 0
+		)`,
+		Args: []interface{}{psgcCode},
+	}
+}
+
+func (p *dbHierarchyRepository) paginate(
+	ctx context.Context,
+	params domain.PaginationParams,
+	filters ...Filter,
+) (domain.PaginatedGeoUnit, error) {
+	lst, metaData, err := p.table.Paginate(ctx, params, filters...)
+	if err != nil {
+		return domain.PaginatedGeoUnit{}, err
+	}
+
+	return domain.PaginatedGeoUnit{MetaData: metaData, Data: lst}, nil
+}
+
+func (p *dbHierarchyRepository) GetAll(
+	ctx context.Context,
+	level string,
+	params domain.PaginationParams,
+) (domain.PaginatedGeoUnit, error) {
+	return p.paginate(ctx, params, levelFilters(level, params.Parent)...)
+}
+
+func (p *dbHierarchyRepository) Each(
+	ctx context.Context,
+	level string,
+	params domain.PaginationParams,
+	fn func(item domain.GeoUnit) error,
+) error {
+	return p.table.Each(ctx, params, fn, levelFilters(level, params.Parent)...)
+}
+
+// levelFilters filters the units of a level right below parentCode, either
+// may be empty
+func levelFilters(level, parentCode string) []Filter {
+	filters := ParentFilter("parent_code", parentCode)
+	if level != "" {
+		filters = append(filters, Eq("level", level))
+	}
+	return filters
+}
+
+func (p *dbHierarchyRepository) GetById(
+	ctx context.Context,
+	psgcCode string,
+) (domain.GeoUnit, error) {
+	return p.table.GetById(ctx, psgcCode)
+}
+
+func (p *dbHierarchyRepository) GetByIds(
+	ctx context.Context,
+	psgcCodes []string,
+) ([]domain.GeoUnit, error) {
+	return p.table.All(ctx, In("psgc_code", psgcCodes))
+}
+
+func (p *dbHierarchyRepository) Ancestors(
+	ctx context.Context,
+	psgcCode string,
+) ([]domain.GeoUnit, error) {
+	if _, err := p.table.GetById(ctx, psgcCode); err != nil {
+		return nil, err
+	}
+
+	columns := "g." + strings.ReplaceAll(p.table.columnNames(), ", ", ", g.")
+	query := `SELECT ` + columns + `
+		FROM geo_unit_closure c
+		JOIN geo_unit g ON g.psgc_code = c.ancestor
+		WHERE c.descendant = ? AND c.depth > 0
+		ORDER BY c.depth DESC`
+
+	lst, err := p.table.fetch(ctx, query, psgcCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lst) == 0 {
+		lst = []domain.GeoUnit{}
+	}
+	return lst, nil
+}
+
+func (p *dbHierarchyRepository) Children(
+	ctx context.Context,
+	psgcCode string,
+	params domain.PaginationParams,
+) (domain.PaginatedGeoUnit, error) {
+	return p.paginate(ctx, params, Eq("parent_code", psgcCode))
+}
+
+// descendantFilters filters the units of a level below psgcCode
+func descendantFilters(psgcCode, level string) []Filter {
+	filters := []Filter{descendantOf(psgcCode)}
+	if level != "" {
+		filters = append(filters, Eq("level", level))
+	}
+	return filters
+}
+
+func (p *dbHierarchyRepository) Descendants(
+	ctx context.Context,
+	psgcCode string,
+	level string,
+	params domain.PaginationParams,
+) (domain.PaginatedGeoUnit, error) {
+	return p.paginate(ctx, params, descendantFilters(psgcCode, level)...)
+}
+
+func (p *dbHierarchyRepository) EachDescendant(
+	ctx context.Context,
+	psgcCode string,
+	level string,
+	params domain.PaginationParams,
+	fn func(item domain.GeoUnit) error,
+) error {
+	return p.table.Each(ctx, params, fn, descendantFilters(psgcCode, level)...)
+}
+
+func (p *dbHierarchyRepository) Create(
+	ctx context.Context,
+	data *domain.Masterlist,
+) error {
+	// Units without a parent at the level above, like independent cities,
+	// hang off their region
+	parentCode := data.ParentCode
+	if parentCode == "" && data.Level != domain.LevelRegion {
+		parentCode = domain.RegionOf(data.PsgcCode)
+	}
+
+	return p.table.Insert(ctx, domain.GeoUnit{
+		PsgcCode:       data.PsgcCode,
+		Name:           data.Name,
+		Level:          data.Level,
+		ParentCode:     parentCode,
+		Population2015: int(data.Population2015),
+		Population2020: int(data.Population2020),
+	})
+}
+
+func (p *dbHierarchyRepository) BuildClosure(ctx context.Context) error {
+	query := `
+		INSERT OR IGNORE INTO geo_unit_closure (ancestor, descendant, depth)
+		WITH RECURSIVE closure (ancestor, descendant, depth) AS (
+			SELECT psgc_code, psgc_code, 0 FROM geo_unit
+			UNION ALL
+			SELECT g.parent_code, c.descendant, c.depth + 1
+			FROM closure c
+			JOIN geo_unit g ON g.psgc_code = c.ancestor
+			WHERE g.parent_code IS NOT NULL
+		)
+		SELECT ancestor, descendant, depth FROM closure;`
+
+	ctx, span := spanWithQuery(ctx, p.table.tracer, query)
+	defer span.End()
+
+	if _, err := p.table.conn.ExecContext(ctx, query); err != nil {
+		span.SetStatus(codes.Error, "failed building geo_unit closure")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
@@ -3,168 +3,85 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"strings"
 
 	"github.com/Brix101/psgc-tool/internal/domain"
-	"go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/codes"
-	"go.opentelemetry.io/otel/trace"
 )
 
+var provinceColumns = []Column[domain.Province]{
+	{"psgc_code", func(p *domain.Province) interface{} { return &p.PsgcCode }},
+	{"reg_code", func(p *domain.Province) interface{} { return &p.RegCode }},
+	{"name", func(p *domain.Province) interface{} { return &p.Name }},
+	{"income_class", func(p *domain.Province) interface{} { return &p.IncomeClass }},
+	{"population_2015", func(p *domain.Province) interface{} { return &p.Population2015 }},
+	{"population_2020", func(p *domain.Province) interface{} { return &p.Population2020 }},
+}
+
 type dbProvinceRepository struct {
-	conn   Connection
-	tracer trace.Tracer
+	table *Table[domain.Province]
 }
 
 func NewDBProvince(conn *sql.DB) domain.ProvinceRepository {
-	tracer := otel.Tracer("db:sqlite3:province")
+	table := NewTable(conn, "province", provinceColumns, domain.ProvinceSortFields)
 
-	return &dbProvinceRepository{conn: conn, tracer: tracer}
+	return &dbProvinceRepository{table: table}
 }
 
-func (p *dbProvinceRepository) fetch(
-	ctx context.Context,
-	query string,
-	args ...interface{},
-) ([]domain.Province, error) {
-	ctx, span := spanWithQuery(ctx, p.tracer, query)
-	defer span.End()
-
-	rows, err := p.conn.QueryContext(ctx, query, args...)
-	if err != nil {
-		span.SetStatus(codes.Error, "failed querying province")
-		span.RecordError(err)
-		return nil, err
-	}
-	defer rows.Close()
-
-	var mLst []domain.Province
-	for rows.Next() {
-		var lst domain.Province
-		if err := rows.Scan(
-			&lst.PsgcCode,
-			&lst.RegCode,
-			&lst.Name,
-		); err != nil {
-			return nil, err
-		}
-		mLst = append(mLst, lst)
-	}
-	return mLst, nil
+// listFilters limits a list to the parent and the attribute values of params
+func (p *dbProvinceRepository) listFilters(params domain.PaginationParams) []Filter {
+	return append(ParentFilter("reg_code", params.Parent), AttributeFilters(params, domain.ProvinceAttributes)...)
 }
 
-func (p *dbProvinceRepository) paginatedQuery(
+func (p *dbProvinceRepository) GetAll(
 	ctx context.Context,
 	params domain.PaginationParams,
 ) (domain.PaginatedProvince, error) {
-	queryParams := []interface{}{}
-	query := `SELECT * FROM province`
-	countQuery := `SELECT COUNT(*) FROM province`
-
-	if params.Keyword != "" {
-		query += `
-			WHERE (
-                LOWER(psgc_code) LIKE '%' || LOWER($1) || '%' OR
-                LOWER(name) LIKE '%' || LOWER($1) || '%' 
-            )
-        `
-		queryParams = append(queryParams, params.Keyword)
-	}
-
-	// Add sorting by name in ascending order.
-	query += `
-        ORDER BY name ASC
-        LIMIT $2
-        OFFSET $3
-    `
-
-	queryParams = append(queryParams, params.PerPage, (params.Page-1)*params.PerPage)
-
-	// Execute the query with appropriate parameters.
-	lst, err := p.fetch(ctx, query, queryParams...)
+	lst, metaData, err := p.table.Paginate(ctx, params, p.listFilters(params)...)
 	if err != nil {
 		return domain.PaginatedProvince{}, err
 	}
 
-	totalItems := 0
-	if err := p.conn.QueryRowContext(ctx, countQuery).Scan(&totalItems); err != nil {
-		return domain.PaginatedProvince{}, err
-	}
-
-	totalPages := (totalItems + params.PerPage - 1) / params.PerPage
-
-	if len(lst) == 0 {
-		lst = []domain.Province{}
-	}
-
-	metaData := domain.MetaData{
-		Page:       params.Page,
-		TotalPages: totalPages,
-		PerPage:    params.PerPage,
-		TotalItems: totalItems,
-		ItemCount:  len(lst),
-	}
-
-	res := domain.PaginatedProvince{
-		MetaData: metaData,
-		Data:     lst,
-	}
-
-	return res, nil
-}
-
-func (p *dbProvinceRepository) GetAll(
-	ctx context.Context,
-	params domain.PaginationParams,
-) (domain.PaginatedProvince, error) {
-	res, err := p.paginatedQuery(ctx, params)
-
-	return res, err
+	return domain.PaginatedProvince{MetaData: metaData, Data: lst}, nil
 }
 
 func (p *dbProvinceRepository) GetById(
 	ctx context.Context,
 	psgcCode string,
 ) (domain.Province, error) {
-	query := `SELECT * FROM province WHERE psgc_code = $1`
+	return p.table.GetById(ctx, psgcCode)
+}
 
-	accs, err := p.fetch(ctx, query, psgcCode)
-	if err != nil {
-		return domain.Province{}, err
-	}
+func (p *dbProvinceRepository) GetByIds(
+	ctx context.Context,
+	psgcCodes []string,
+) ([]domain.Province, error) {
+	return p.table.All(ctx, In("psgc_code", psgcCodes))
+}
 
-	if len(accs) == 0 {
-		return domain.Province{}, domain.ErrNotFound
-	}
-	return accs[0], nil
+func (p *dbProvinceRepository) GetByParents(
+	ctx context.Context,
+	parentCodes []string,
+) ([]domain.Province, error) {
+	return p.table.All(ctx, In("reg_code", parentCodes))
+}
+
+func (p *dbProvinceRepository) Each(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.Province) error,
+) error {
+	return p.table.Each(ctx, params, fn, p.listFilters(params)...)
 }
 
 func (p *dbProvinceRepository) Create(
 	ctx context.Context,
 	data *domain.Masterlist,
 ) error {
-	query := `
-		INSERT OR REPLACE INTO province (psgc_code, name, reg_code)
-		VALUES (?, ?, ?);`
-
-	ctx, span := spanWithQuery(ctx, p.tracer, query)
-	defer span.End()
-
-	psgcCode := data.PsgcCode
-	regCode := psgcCode[:2] + strings.Repeat("0", len(psgcCode)-2)
-
-	_, err := p.conn.ExecContext(
-		ctx,
-		query,
-		data.PsgcCode,
-		data.Name,
-		regCode,
-	)
-	if err != nil {
-		span.SetStatus(codes.Error, "failed inserting province")
-		span.RecordError(err)
-		return err
-	}
-
-	return nil
+	return p.table.Insert(ctx, domain.Province{
+		PsgcCode:       data.PsgcCode,
+		RegCode:        data.ParentCode,
+		Name:           data.Name,
+		IncomeClass:    data.IncomeClass,
+		Population2015: int(data.Population2015),
+		Population2020: int(data.Population2020),
+	})
 }
@@ -0,0 +1,235 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"go.opentelemetry.io/otel/codes"
+)
+
+var statsColumns = []Column[domain.Stats]{
+	{"psgc_code", func(s *domain.Stats) interface{} { return &s.PsgcCode }},
+	{"name", func(s *domain.Stats) interface{} { return &s.Name }},
+	{"level", func(s *domain.Stats) interface{} { return &s.Level }},
+	{"regions", func(s *domain.Stats) interface{} { return &s.Regions }},
+	{"provinces", func(s *domain.Stats) interface{} { return &s.Provinces }},
+	{"cities", func(s *domain.Stats) interface{} { return &s.Cities }},
+	{"municipalities", func(s *domain.Stats) interface{} { return &s.Municipalities }},
+	{"sgus", func(s *domain.Stats) interface{} { return &s.SGUs }},
+	{"barangays", func(s *domain.Stats) interface{} { return &s.Barangays }},
+	{"population_2015", func(s *domain.Stats) interface{} { return &s.Population2015 }},
+	{"population_2020", func(s *domain.Stats) interface{} { return &s.Population2020 }},
+	{"urban_barangays", func(s *domain.Stats) interface{} { return &s.UrbanBarangays }},
+	{"rural_barangays", func(s *domain.Stats) interface{} { return &s.RuralBarangays }},
+	{"urban_population_2020", func(s *domain.Stats) interface{} { return &s.UrbanPopulation2020 }},
+	{"rural_population_2020", func(s *domain.Stats) interface{} { return &s.RuralPopulation2020 }},
+}
+
+var rankedUnitColumns = []Column[domain.RankedUnit]{
+	{"rank", func(u *domain.RankedUnit) interface{} { return &u.Rank }},
+	{"psgc_code", func(u *domain.RankedUnit) interface{} { return &u.PsgcCode }},
+	{"name", func(u *domain.RankedUnit) interface{} { return &u.Name }},
+	{"level", func(u *domain.RankedUnit) interface{} { return &u.Level }},
+	{"parent_code", func(u *domain.RankedUnit) interface{} { return nullString{&u.ParentCode} }},
+	{"population_2015", func(u *domain.RankedUnit) interface{} { return &u.Population2015 }},
+	{"population_2020", func(u *domain.RankedUnit) interface{} { return &u.Population2020 }},
+}
+
+// rankingMetrics are the SQL expressions the units are ordered by for each
+// metric. The growth rate is ordered by the ratio of the populations, which
+// orders the units the same way without SQLite's math functions.
+var rankingMetrics = map[string]string{
+	domain.MetricPopulation2015:   "population_2015",
+	domain.MetricPopulation2020:   "population_2020",
+	domain.MetricPopulationChange: "population_2020 - population_2015",
+	domain.MetricGrowthRate:       "CAST(population_2020 AS REAL) / population_2015",
+}
+
+// rankingValue returns the metric of a unit as the ranking shows it, the
+// units showing the same value share a rank. The growth rate is rounded, so
+// units with different ratios may tie.
+func rankingValue(u domain.RankedUnit, metric string) float64 {
+	switch metric {
+	case domain.MetricPopulation2015:
+		return float64(u.Population2015)
+	case domain.MetricPopulation2020:
+		return float64(u.Population2020)
+	case domain.MetricPopulationChange:
+		return float64(u.PopulationChange)
+	}
+	return u.GrowthRate
+}
+
+// errRanked stops reading the units of a ranking past its last rank
+var errRanked = errors.New("ranked")
+
+// NationalName is the name of the stats of the whole country
+const NationalName = "Philippines"
+
+type dbStatsRepository struct {
+	table    *Table[domain.Stats]
+	rankings *Table[domain.RankedUnit]
+}
+
+// NewDBStats reads the stats through the geo_unit_summary view, which joins
+// the units with their stats
+func NewDBStats(conn *sql.DB) domain.StatsRepository {
+	table := NewTable(conn, "geo_unit_summary", statsColumns, []string{"psgc_code"})
+
+	rankings := NewTable(conn, "geo_unit", rankedUnitColumns, []string{"rank"})
+
+	return &dbStatsRepository{table: table, rankings: rankings}
+}
+
+// withGrowth fills the fields derived from the populations
+func withGrowth(stats domain.Stats) domain.Stats {
+	stats.PopulationChange = stats.Population2020 - stats.Population2015
+	stats.GrowthRate = domain.GrowthRate(stats.Population2015, stats.Population2020)
+
+	return stats
+}
+
+func (p *dbStatsRepository) Get(
+	ctx context.Context,
+	psgcCode string,
+) (domain.Stats, error) {
+	stats, err := p.table.GetById(ctx, psgcCode)
+	if err != nil {
+		return stats, err
+	}
+
+	return withGrowth(stats), nil
+}
+
+// National adds up the regions, the populations of the masterlist are
+// counted at every level so the regions' add up to the country's
+func (p *dbStatsRepository) National(ctx context.Context) (domain.Stats, error) {
+	query := `SELECT
+			'', ?, '',
+			COUNT(*), IFNULL(SUM(provinces), 0), IFNULL(SUM(cities), 0),
+			IFNULL(SUM(municipalities), 0), IFNULL(SUM(sgus), 0), IFNULL(SUM(barangays), 0),
+			IFNULL(SUM(population_2015), 0), IFNULL(SUM(population_2020), 0),
+			IFNULL(SUM(urban_barangays), 0), IFNULL(SUM(rural_barangays), 0),
+			IFNULL(SUM(urban_population_2020), 0), IFNULL(SUM(rural_population_2020), 0)
+		FROM geo_unit_summary
+		WHERE level = ?`
+
+	lst, err := p.table.fetch(ctx, query, NationalName, domain.LevelRegion)
+	if err != nil {
+		return domain.Stats{}, err
+	}
+
+	return withGrowth(lst[0]), nil
+}
+
+func (p *dbStatsRepository) Rankings(
+	ctx context.Context,
+	params domain.RankingParams,
+) ([]domain.RankedUnit, error) {
+	metric, ok := rankingMetrics[params.Metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown ranking metric %q", params.Metric)
+	}
+
+	order := "DESC"
+	if params.Order == domain.OrderAsc {
+		order = "ASC"
+	}
+
+	filters := []Filter{Eq("level", params.Level)}
+	if params.Scope != "" {
+		filters = append(filters, descendantOf(params.Scope))
+	}
+	if params.Metric == domain.MetricGrowthRate {
+		filters = append(filters, Filter{Condition: "population_2015 > 0"})
+	}
+
+	conditions := []string{}
+	args := []interface{}{}
+	for _, filter := range filters {
+		conditions = append(conditions, filter.Condition)
+		args = append(args, filter.Args...)
+	}
+
+	// The units come in the order of the metric and are ranked on the value
+	// they show, a unit tied with the one before it shares its rank. Every
+	// unit tied at the last rank is returned.
+	query := `SELECT 0, psgc_code, name, level, parent_code, population_2015, population_2020
+		FROM geo_unit
+		WHERE ` + strings.Join(conditions, " AND ") + `
+		ORDER BY ` + metric + ` ` + order + `, psgc_code`
+
+	lst := []domain.RankedUnit{}
+	var last float64
+	err := p.rankings.each(ctx, query, args, func(u domain.RankedUnit) error {
+		u.PopulationChange = u.Population2020 - u.Population2015
+		u.GrowthRate = domain.GrowthRate(u.Population2015, u.Population2020)
+
+		value := rankingValue(u, params.Metric)
+		u.Rank = len(lst) + 1
+		if len(lst) > 0 && value == last {
+			u.Rank = lst[len(lst)-1].Rank
+		}
+		if u.Rank > params.Limit {
+			return errRanked
+		}
+
+		last = value
+		lst = append(lst, u)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errRanked) {
+		return nil, err
+	}
+
+	// Units tied on a rounded growth rate come in the order of their ratios
+	sort.SliceStable(lst, func(i, j int) bool {
+		if lst[i].Rank != lst[j].Rank {
+			return lst[i].Rank < lst[j].Rank
+		}
+		return lst[i].PsgcCode < lst[j].PsgcCode
+	})
+
+	return lst, nil
+}
+
+func (p *dbStatsRepository) Build(ctx context.Context) error {
+	query := `
+		INSERT OR REPLACE INTO geo_unit_stats (
+			psgc_code, regions, provinces, cities, municipalities, sgus, barangays,
+			urban_barangays, rural_barangays, urban_population_2020, rural_population_2020
+		)
+		SELECT
+			u.psgc_code,
+			COUNT(CASE WHEN g.level = 'Reg' THEN 1 END),
+			COUNT(CASE WHEN g.level = 'Prov' THEN 1 END),
+			COUNT(CASE WHEN g.level = 'City' THEN 1 END),
+			COUNT(CASE WHEN g.level = 'Mun' THEN 1 END),
+			COUNT(CASE WHEN g.level = 'SGU' THEN 1 END),
+			COUNT(CASE WHEN g.level = 'Bgy' THEN 1 END),
+			COUNT(CASE WHEN b.urban_rural = 'U' THEN 1 END),
+			COUNT(CASE WHEN b.urban_rural = 'R' THEN 1 END),
+			SUM(CASE WHEN b.urban_rural = 'U' THEN b.population_2020 ELSE 0 END),
+			SUM(CASE WHEN b.urban_rural = 'R' THEN b.population_2020 ELSE 0 END)
+		FROM geo_unit u
+		LEFT JOIN geo_unit_closure c ON c.ancestor = u.psgc_code AND c.depth > 0
+		LEFT JOIN geo_unit g ON g.psgc_code = c.descendant
+		LEFT JOIN barangay b ON b.psgc_code = c.descendant
+		GROUP BY u.psgc_code;`
+
+	ctx, span := spanWithQuery(ctx, p.table.tracer, query)
+	defer span.End()
+
+	if _, err := p.table.conn.ExecContext(ctx, query); err != nil {
+		span.SetStatus(codes.Error, "failed building geo_unit stats")
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// TestStatsBuild rolls up a small hierarchy: a region with a province of a
+// municipality and a city, an independent city hanging off the region, and
+// a second region with a city
+func TestStatsBuild(t *testing.T) {
+	db := newMigratedDB(t)
+	ctx := context.Background()
+
+	regions, provinces := NewDBRegion(db), NewDBProvince(db)
+	citiesMunis, barangays := NewDBCityMuni(db), NewDBBarangay(db)
+	hierarchy, stats := NewDBHierarchy(db), NewDBStats(db)
+
+	units := []domain.Masterlist{
+		{PsgcCode: "0100000000", Name: "Region I", Level: domain.LevelRegion, Population2015: 1000, Population2020: 1100},
+		{PsgcCode: "0102800000", Name: "Ilocos Norte", Level: domain.LevelProvince, ParentCode: "0100000000", Population2015: 600, Population2020: 650},
+		{PsgcCode: "0102801000", Name: "Adams", Level: domain.LevelMunicipality, ParentCode: "0102800000", Population2015: 60, Population2020: 55},
+		{PsgcCode: "0102801001", Name: "Adams Pob.", Level: domain.LevelBarangay, ParentCode: "0102801000", UrbanRural: "U", Population2020: 30},
+		{PsgcCode: "0102801002", Name: "Bucarot", Level: domain.LevelBarangay, ParentCode: "0102801000", UrbanRural: "R", Population2020: 20},
+		{PsgcCode: "0102801003", Name: "Unclassified", Level: domain.LevelBarangay, ParentCode: "0102801000", Population2020: 5},
+		{PsgcCode: "0102802000", Name: "City of Batac", Level: domain.LevelCity, ParentCode: "0102800000", Population2015: 500, Population2020: 595},
+		{PsgcCode: "0102802001", Name: "Aglipay", Level: domain.LevelBarangay, ParentCode: "0102802000", UrbanRural: "U", Population2020: 595},
+		{PsgcCode: "0135500000", Name: "City of Dagupan", Level: domain.LevelCity, Population2015: 400, Population2020: 450},
+		{PsgcCode: "0135500001", Name: "Bacayao", Level: domain.LevelBarangay, ParentCode: "0135500000", UrbanRural: "R", Population2020: 450},
+		{PsgcCode: "1300000000", Name: "NCR", Level: domain.LevelRegion, Population2015: 200, Population2020: 210},
+		{PsgcCode: "1380100000", Name: "City of Caloocan", Level: domain.LevelCity, Population2015: 200, Population2020: 210},
+		{PsgcCode: "1380100001", Name: "Barangay 1", Level: domain.LevelBarangay, ParentCode: "1380100000", UrbanRural: "U", Population2020: 210},
+	}
+	for i := range units {
+		data := &units[i]
+
+		var err error
+		switch data.Level {
+		case domain.LevelRegion:
+			err = regions.Create(ctx, data)
+		case domain.LevelProvince:
+			err = provinces.Create(ctx, data)
+		case domain.LevelBarangay:
+			err = barangays.Create(ctx, data)
+		default:
+			err = citiesMunis.Create(ctx, data)
+		}
+		if err == nil {
+			err = hierarchy.Create(ctx, data)
+		}
+		if err != nil {
+			t.Fatalf("creating %s: %v", data.PsgcCode, err)
+		}
+	}
+
+	if err := hierarchy.BuildClosure(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := stats.Build(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		psgcCode string
+		want     domain.Stats
+	}{
+		{
+			psgcCode: "0100000000",
+			want: domain.Stats{
+				PsgcCode: "0100000000", Name: "Region I", Level: domain.LevelRegion,
+				Provinces: 1, Cities: 2, Municipalities: 1, Barangays: 5,
+				Population2015: 1000, Population2020: 1100, PopulationChange: 100, GrowthRate: domain.GrowthRate(1000, 1100),
+				UrbanBarangays: 2, RuralBarangays: 2, UrbanPopulation2020: 625, RuralPopulation2020: 470,
+			},
+		},
+		{
+			psgcCode: "0102800000",
+			want: domain.Stats{
+				PsgcCode: "0102800000", Name: "Ilocos Norte", Level: domain.LevelProvince,
+				Cities: 1, Municipalities: 1, Barangays: 4,
+				Population2015: 600, Population2020: 650, PopulationChange: 50, GrowthRate: domain.GrowthRate(600, 650),
+				UrbanBarangays: 2, RuralBarangays: 1, UrbanPopulation2020: 625, RuralPopulation2020: 20,
+			},
+		},
+		{
+			psgcCode: "0102801000",
+			want: domain.Stats{
+				PsgcCode: "0102801000", Name: "Adams", Level: domain.LevelMunicipality,
+				Barangays:      3,
+				Population2015: 60, Population2020: 55, PopulationChange: -5, GrowthRate: domain.GrowthRate(60, 55),
+				UrbanBarangays: 1, RuralBarangays: 1, UrbanPopulation2020: 30, RuralPopulation2020: 20,
+			},
+		},
+		{
+			psgcCode: "0102801001",
+			want: domain.Stats{
+				PsgcCode: "0102801001", Name: "Adams Pob.", Level: domain.LevelBarangay,
+				Population2020: 30, PopulationChange: 30,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.psgcCode, func(t *testing.T) {
+			got, err := stats.Get(ctx, tt.psgcCode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Get() = %+v\nwant %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("national", func(t *testing.T) {
+		got, err := stats.National(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := domain.Stats{
+			Name:      NationalName,
+			Regions:   2,
+			Provinces: 1, Cities: 3, Municipalities: 1, Barangays: 6,
+			Population2015: 1200, Population2020: 1310, PopulationChange: 110, GrowthRate: domain.GrowthRate(1200, 1310),
+			UrbanBarangays: 3, RuralBarangays: 2, UrbanPopulation2020: 835, RuralPopulation2020: 470,
+		}
+		if got != want {
+			t.Errorf("National() = %+v\nwant %+v", got, want)
+		}
+	})
+
+	if _, err := stats.Get(ctx, "9900000000"); err != domain.ErrNotFound {
+		t.Errorf("Get(unknown) error = %v, want %v", err, domain.ErrNotFound)
+	}
+}
+
+// TestRankingsTies ranks the units of the tracked edition, where many
+// municipalities show the same rounded growth rate
+func TestRankingsTies(t *testing.T) {
+	stats := NewDBStats(openEdition(t))
+	ctx := context.Background()
+
+	for _, metric := range domain.RankingMetrics {
+		t.Run(metric, func(t *testing.T) {
+			params := domain.RankingParams{Level: domain.LevelMunicipality, Metric: metric, Order: domain.OrderDesc, Limit: 1000}
+			all, err := stats.Rankings(ctx, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(all) < params.Limit {
+				t.Fatalf("Rankings() = %d units, want at least %d", len(all), params.Limit)
+			}
+
+			// A unit shares the rank of the one before it when it shows the
+			// same value, and is ranked after every unit before it otherwise
+			ties := 0
+			for i := 1; i < len(all); i++ {
+				prev, u := all[i-1], all[i]
+				same := rankingValue(prev, metric) == rankingValue(u, metric)
+
+				switch {
+				case same && u.Rank != prev.Rank:
+					t.Fatalf("%s and %s show the same %s but rank %d and %d", prev.PsgcCode, u.PsgcCode, metric, prev.Rank, u.Rank)
+				case !same && u.Rank != i+1:
+					t.Fatalf("%s is the unit %d but ranks %d", u.PsgcCode, i+1, u.Rank)
+				case same && u.PsgcCode < prev.PsgcCode:
+					t.Fatalf("%s and %s are tied out of psgc_code order", prev.PsgcCode, u.PsgcCode)
+				case same:
+					ties++
+				}
+			}
+			if metric == domain.MetricGrowthRate && ties == 0 {
+				t.Error("no tied growth rates")
+			}
+
+			// A limit at a tied rank returns every unit of that rank
+			limit := 0
+			for i := 1; i < len(all) && limit == 0; i++ {
+				if all[i].Rank == all[i-1].Rank && all[i].Rank > 1 {
+					limit = all[i].Rank
+				}
+			}
+			if limit == 0 {
+				return
+			}
+
+			params.Limit = limit
+			got, err := stats.Rankings(ctx, params)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := []domain.RankedUnit{}
+			for _, u := range all {
+				if u.Rank <= limit {
+					want = append(want, u)
+				}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Rankings(limit %d) = %d units, want the %d ranked up to it", limit, len(got), len(want))
+			}
+			if len(got) <= limit {
+				t.Errorf("Rankings(limit %d) = %d units, want the units tied at the limit", limit, len(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/filter"
+)
+
+// Expression adds the condition of a filter expression, it is a no-op for
+// a nil expression
+func (q *queryBuilder) Expression(e filter.Expr) *queryBuilder {
+	if e == nil {
+		return q
+	}
+
+	f := Expression(e)
+	return q.Where(f.Condition, f.Args...)
+}
+
+// Expression compiles a filter expression to a condition with the same
+// results as filter.Eval. Its columns come from the whitelist of the
+// resource it was parsed for, every value is a parameter.
+//
+// Strings compare with NOCASE and LIKE, which both ignore ASCII case only,
+// and a NULL column reads as an empty string. A RegionParent column reads
+// as the region of psgc_code instead.
+func Expression(e filter.Expr) Filter {
+	switch e := e.(type) {
+	case filter.And:
+		return join("AND", Expression(e.Left), Expression(e.Right))
+	case filter.Or:
+		return join("OR", Expression(e.Left), Expression(e.Right))
+	case filter.Not:
+		x := Expression(e.X)
+		return Filter{Condition: "NOT " + x.Condition, Args: x.Args}
+	case filter.Compare:
+		return compare(e)
+	}
+
+	return Filter{Condition: "0"}
+}
+
+func join(op string, left, right Filter) Filter {
+	return Filter{
+		Condition: "(" + left.Condition + " " + op + " " + right.Condition + ")",
+		Args:      append(append([]interface{}{}, left.Args...), right.Args...),
+	}
+}
+
+var sqlOps = map[filter.Op]string{
+	filter.Eq: "=",
+	filter.Ne: "<>",
+	filter.Gt: ">",
+	filter.Ge: ">=",
+	filter.Lt: "<",
+	filter.Le: "<=",
+}
+
+// likeEscaper escapes the wildcards of LIKE, with \ as the escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+func compare(c filter.Compare) Filter {
+	column := c.Column
+	if c.Kind == filter.String {
+		column = "IFNULL(" + c.Column + ", '')"
+		if c.Match == filter.RegionParent {
+			column = "COALESCE(" + c.Column + ", substr(psgc_code, 1, 2) || '00000000')"
+		}
+		column += " COLLATE NOCASE"
+	}
+
+	switch c.Op {
+	case filter.In:
+		if c.Match != filter.Exact {
+			filters := make([]Filter, len(c.Values))
+			for i, value := range c.Values {
+				filters[i] = equals(c, column, value.(string))
+			}
+			return Or(filters...)
+		}
+
+		placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(c.Values)), ", ")
+		return Filter{Condition: "(" + column + " IN (" + placeholders + "))", Args: c.Values}
+	case filter.StartsWith, filter.EndsWith, filter.Contains:
+		pattern := likeEscaper.Replace(c.Values[0].(string))
+		if c.Op != filter.StartsWith {
+			pattern = "%" + pattern
+		}
+		if c.Op != filter.EndsWith {
+			pattern += "%"
+		}
+		return Filter{Condition: "(" + column + ` LIKE ? ESCAPE '\')`, Args: []interface{}{pattern}}
+	}
+
+	value := c.Values[0]
+	if value == nil {
+		// null is the empty string
+		value = ""
+	} else if c.Match != filter.Exact && (c.Op == filter.Eq || c.Op == filter.Ne) {
+		f := equals(c, column, value.(string))
+		if c.Op == filter.Ne {
+			f.Condition = "NOT " + f.Condition
+		}
+		return f
+	}
+
+	return Filter{Condition: "(" + column + " " + sqlOps[c.Op] + " ?)", Args: []interface{}{value}}
+}
+
+// equals is the condition of a column of a Footnoted or RegionParent field
+// equal to value. It is never NULL, so that it can be negated.
+func equals(c filter.Compare, column, value string) Filter {
+	if c.Match == filter.Footnoted {
+		// Like AttributeFilters, without case
+		pattern := likeEscaper.Replace(value)
+		return Filter{
+			Condition: "(" + column + " = ? OR " + column + ` LIKE ? ESCAPE '\' OR ` + column + ` LIKE ? ESCAPE '\')`,
+			Args:      []interface{}{value, pattern + "*%", pattern + " %"},
+		}
+	}
+
+	// Like the parent filter of the cities/municipalities, on the indexed
+	// parent column
+	condition := "(" + c.Column + " IS NOT NULL AND " + c.Column + " = ?)"
+	args := []interface{}{value}
+	if len(value) == 10 && strings.HasSuffix(value, "00000000") {
+		condition = "(" + condition + " OR (" + c.Column + ` IS NULL AND psgc_code LIKE ? ESCAPE '\'))`
+		args = append(args, likeEscaper.Replace(value[:2])+"%")
+	}
+
+	return Filter{Condition: condition, Args: args}
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"database/sql"
+	"slices"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/filter"
+)
+
+// TestExpressionMatchesEval checks that the SQL of an expression selects the
+// rows filter.Eval matches
+func TestExpressionMatchesEval(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(`CREATE TABLE city_muni (
+		psgc_code TEXT PRIMARY KEY,
+		name TEXT NOT NULL COLLATE NOCASE,
+		prov_code TEXT,
+		income_class TEXT NOT NULL,
+		population_2020 INTEGER NOT NULL
+	)`); err != nil {
+		t.Fatal(err)
+	}
+
+	type row struct {
+		psgcCode, name, provCode, incomeClass string
+		population                            int
+	}
+	rows := []row{
+		// In psgc_code order, like the query
+		{"0102801000", "Adams", "0102800000", "5th", 2189},
+		{"0102802000", "Bacarra", "0102800000", "1st (as Mun)", 33496},
+		{"0102900000", "Ilocos_Sur 100%", "", "1st*", 706009},
+		{"1300000000", "prov_code region", "1300000000", "Special", 0},
+		{"1380100000", "City of Las Pi\xf1as", "", "1st", 606293},
+		{"1381701000", "Pateros", "", "", 65227},
+	}
+	for _, r := range rows {
+		var provCode interface{}
+		if r.provCode != "" {
+			provCode = r.provCode
+		}
+		if _, err := db.Exec("INSERT INTO city_muni VALUES (?, ?, ?, ?, ?)", r.psgcCode, r.name, provCode, r.incomeClass, r.population); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fields := filter.Fields{
+		"name":            {Kind: filter.String},
+		"prov_code":       {Kind: filter.String},
+		"parent_code":     {Kind: filter.String, Column: "prov_code", Match: filter.RegionParent},
+		"income_class":    {Kind: filter.String, Match: filter.Footnoted},
+		"population_2020": {Kind: filter.Number},
+	}
+
+	tests := []string{
+		"name eq 'adams'",
+		"name eq 'CITY OF LAS PI\xf1AS'",
+		"name contains '_'",
+		"name endswith '100%'",
+		"name startswith 'b' or population_2020 gt 600000 and name contains 'city'",
+		"not (name startswith 'b' or population_2020 gt 600000) and name contains 'a'",
+		"prov_code eq null",
+		"prov_code ne null",
+		"prov_code in ('0102800000', '1300000000')",
+		"income_class eq '1st'",
+		"income_class eq '1ST'",
+		"income_class ne '1st'",
+		"income_class eq '1st*'",
+		"income_class in ('5th', '1st')",
+		"income_class eq null",
+		"income_class ne 'special'",
+		"income_class gt '1st'",
+		"parent_code eq '1300000000'",
+		"parent_code eq '0100000000'",
+		"parent_code eq '0102800000'",
+		"parent_code ne '1300000000'",
+		"parent_code ne '0102800000'",
+		"parent_code in ('0100000000', '0102800000', '1300000000')",
+		"not parent_code in ('0102800000')",
+		"parent_code startswith '13'",
+		"parent_code gt '0102800000'",
+		"parent_code eq null",
+		"parent_code ne null",
+		"parent_code eq '%_00000000'",
+	}
+
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			e, err := filter.Parse(src, fields)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+
+			want := []string{}
+			for _, r := range rows {
+				values := map[string]interface{}{
+					"psgc_code":       r.psgcCode,
+					"name":            r.name,
+					"prov_code":       r.provCode,
+					"income_class":    r.incomeClass,
+					"population_2020": r.population,
+				}
+				if filter.Eval(e, func(column string) interface{} { return values[column] }) {
+					want = append(want, r.psgcCode)
+				}
+			}
+
+			f := Expression(e)
+			res, err := db.Query("SELECT psgc_code FROM city_muni WHERE "+f.Condition+" ORDER BY psgc_code", f.Args...)
+			if err != nil {
+				t.Fatalf("%s: %v", f.Condition, err)
+			}
+			defer res.Close()
+
+			got := []string{}
+			for res.Next() {
+				var code string
+				if err := res.Scan(&code); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, code)
+			}
+
+			if !slices.Equal(got, want) {
+				t.Errorf("%s selects %v, Eval matches %v", f.Condition, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// keysetCondition returns the WHERE condition that selects the rows after
+// the cursor row in the cursor's sort order, or before it when the cursor
+// pages backward. The cursor's sort field goes through the same whitelist as
+// orderBy.
+func keysetCondition(
+	cursor domain.Cursor,
+	sortFields []string,
+) (string, []interface{}) {
+	column := sortColumn(cursor.Sort, sortFields)
+
+	op := ">"
+	if cursor.Order == domain.OrderDesc {
+		op = "<"
+	}
+	if cursor.Backward {
+		op = map[string]string{">": "<", "<": ">"}[op]
+	}
+
+	if column == "psgc_code" {
+		return fmt.Sprintf("psgc_code %s ?", op), []interface{}{cursor.PsgcCode}
+	}
+
+	return fmt.Sprintf("(%s, psgc_code) %s (?, ?)", column, op),
+		[]interface{}{cursor.Value, cursor.PsgcCode}
+}
+
+// CursorParams returns the params to run a keyset query with. Backward
+// cursors read the rows closest to the cursor first, so the order is
+// flipped and the rows are reversed again by KeysetPage.
+func CursorParams(params domain.PaginationParams, cursor domain.Cursor) domain.PaginationParams {
+	params.Sort = cursor.Sort
+	params.Order = cursor.Order
+	if cursor.Backward {
+		params.Order = map[string]string{
+			domain.OrderAsc:  domain.OrderDesc,
+			domain.OrderDesc: domain.OrderAsc,
+		}[cursor.Order]
+	}
+
+	return params
+}
+
+// PageLinks tells whether a page has neighbours and the cursors that reach
+// them
+type PageLinks struct {
+	HasNext, HasPrev bool
+	Next, Prev       string
+}
+
+// KeysetPage trims the lookahead row from a page that was queried with
+// LIMIT per_page + 1 and returns the page with its links. sortValue reads
+// the value of the sort field from a row.
+func KeysetPage[T any](
+	lst []T,
+	params domain.PaginationParams,
+	cursor *domain.Cursor,
+	sortValue func(item T, field string) (interface{}, string),
+) ([]T, PageLinks) {
+	hasMore := len(lst) > params.PerPage
+	if hasMore {
+		lst = lst[:params.PerPage]
+	}
+
+	backward := cursor != nil && cursor.Backward
+	if backward {
+		for i, j := 0, len(lst)-1; i < j; i, j = i+1, j-1 {
+			lst[i], lst[j] = lst[j], lst[i]
+		}
+	}
+
+	// Going forward there is a next page when the lookahead row was found,
+	// and a previous one whenever the list didn't start at the first row.
+	links := PageLinks{HasNext: hasMore, HasPrev: cursor != nil || params.Page > 1}
+	if backward {
+		links.HasNext, links.HasPrev = true, hasMore
+	}
+
+	if len(lst) == 0 {
+		// Without a row there is nothing to build a cursor from
+		return lst, PageLinks{HasPrev: cursor == nil && params.Page > 1}
+	}
+
+	sort, order := params.Sort, params.Order
+	if cursor != nil {
+		sort, order = cursor.Sort, cursor.Order
+	}
+
+	newCursor := func(item T, backward bool) string {
+		value, psgcCode := sortValue(item, sort)
+		c := domain.Cursor{Sort: sort, Order: order, PsgcCode: psgcCode, Backward: backward}
+		if sort != "psgc_code" {
+			c.Value = value
+		}
+		return c.Encode()
+	}
+
+	if links.HasNext {
+		links.Next = newCursor(lst[len(lst)-1], false)
+	}
+	if links.HasPrev {
+		links.Prev = newCursor(lst[0], true)
+	}
+
+	return lst, links
+}
+
+// NewMetaData describes a page of itemCount rows out of totalItems
+func NewMetaData(
+	params domain.PaginationParams,
+	cursor *domain.Cursor,
+	totalItems, itemCount int,
+	links PageLinks,
+) domain.MetaData {
+	page := params.Page
+	if cursor != nil {
+		// The page number of a cursor is unknown
+		page = 0
+	}
+
+	return domain.MetaData{
+		Page:       page,
+		TotalPages: (totalItems + params.PerPage - 1) / params.PerPage,
+		PerPage:    params.PerPage,
+		TotalItems: totalItems,
+		ItemCount:  itemCount,
+		HasNext:    links.HasNext,
+		HasPrev:    links.HasPrev,
+		NextCursor: links.Next,
+		PrevCursor: links.Prev,
+	}
+}
@@ -0,0 +1,103 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+var barangaySchema = schema[domain.Barangay]{
+	sortFields: domain.BarangaySortFields,
+	code:       func(b *domain.Barangay) string { return b.PsgcCode },
+	name:       func(b *domain.Barangay) string { return b.Name },
+	parent:     func(b *domain.Barangay) string { return b.CityMuniCode },
+	attributes: domain.BarangayAttributes,
+	attribute: func(b *domain.Barangay, name string) string {
+		if name == domain.UrbanRural.Name {
+			return b.UrbanRural
+		}
+		return b.Status
+	},
+	value: func(b *domain.Barangay, field string) interface{} {
+		switch field {
+		case "name":
+			return b.Name
+		case "citmun_code":
+			return b.CityMuniCode
+		case "urban_rural":
+			return b.UrbanRural
+		case "status":
+			return b.Status
+		case "population_2015":
+			return b.Population2015
+		case "population_2020":
+			return b.Population2020
+		}
+		return b.PsgcCode
+	},
+}
+
+type barangayRepository struct {
+	table *table[domain.Barangay]
+}
+
+func NewBarangay(items []domain.Barangay) domain.BarangayRepository {
+	return &barangayRepository{table: newTable(barangaySchema, items)}
+}
+
+func (p *barangayRepository) GetAll(
+	_ context.Context,
+	params domain.PaginationParams,
+) (domain.PaginatedBarangay, error) {
+	lst, metaData, err := p.table.paginate(params, nil)
+	if err != nil {
+		return domain.PaginatedBarangay{}, err
+	}
+
+	return domain.PaginatedBarangay{MetaData: metaData, Data: lst}, nil
+}
+
+func (p *barangayRepository) GetById(
+	_ context.Context,
+	psgcCode string,
+) (domain.Barangay, error) {
+	return p.table.get(psgcCode, nil)
+}
+
+func (p *barangayRepository) GetByIds(
+	_ context.Context,
+	psgcCodes []string,
+) ([]domain.Barangay, error) {
+	return p.table.getMany(psgcCodes), nil
+}
+
+func (p *barangayRepository) GetByParents(
+	_ context.Context,
+	parentCodes []string,
+) ([]domain.Barangay, error) {
+	return p.table.children(parentCodes), nil
+}
+
+func (p *barangayRepository) Each(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.Barangay) error,
+) error {
+	return p.table.each(ctx, params, nil, fn)
+}
+
+func (p *barangayRepository) Create(
+	_ context.Context,
+	data *domain.Masterlist,
+) error {
+	p.table.insert(domain.Barangay{
+		PsgcCode:       data.PsgcCode,
+		CityMuniCode:   data.ParentCode,
+		Name:           data.Name,
+		UrbanRural:     data.UrbanRural,
+		Status:         data.Status,
+		Population2015: int(data.Population2015),
+		Population2020: int(data.Population2020),
+	})
+	return nil
+}
@@ -0,0 +1,170 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+var cityMuniSchema = schema[domain.CityMuni]{
+	sortFields: domain.CityMuniSortFields,
+	code:       func(c *domain.CityMuni) string { return c.PsgcCode },
+	name:       func(c *domain.CityMuni) string { return c.Name },
+	// Independent cities, Pateros and SGUs have no province, they are the
+	// children of their region instead
+	parent: (*domain.CityMuni).ParentCode,
+	value: func(c *domain.CityMuni, field string) interface{} {
+		switch field {
+		case "name":
+			return c.Name
+		case "prov_code":
+			return c.ProvCode
+		case "level":
+			return c.Level
+		case "city_class":
+			return c.CityClass
+		case "income_class":
+			return c.IncomeClass
+		case "status":
+			return c.Status
+		case "population_2015":
+			return c.Population2015
+		case "population_2020":
+			return c.Population2020
+		}
+		return c.PsgcCode
+	},
+	attributes: domain.CityMuniAttributes,
+	attribute: func(c *domain.CityMuni, name string) string {
+		switch name {
+		case domain.CityClass.Name:
+			return c.CityClass
+		case domain.IncomeClass.Name:
+			return c.IncomeClass
+		}
+		return c.Status
+	},
+}
+
+type cityMuniRepository struct {
+	table *table[domain.CityMuni]
+}
+
+func NewCityMuni(items []domain.CityMuni) domain.CityMuniRepository {
+	return &cityMuniRepository{table: newTable(cityMuniSchema, items)}
+}
+
+// level filters the cities and municipalities by level
+func level(name string) func(item *domain.CityMuni) bool {
+	return func(item *domain.CityMuni) bool { return item.Level == name }
+}
+
+func (p *cityMuniRepository) paginate(
+	params domain.PaginationParams,
+	filter func(item *domain.CityMuni) bool,
+) (domain.PaginatedCityMuni, error) {
+	lst, metaData, err := p.table.paginate(params, filter)
+	if err != nil {
+		return domain.PaginatedCityMuni{}, err
+	}
+
+	return domain.PaginatedCityMuni{MetaData: metaData, Data: lst}, nil
+}
+
+func (p *cityMuniRepository) GetAll(
+	_ context.Context,
+	params domain.PaginationParams,
+) (domain.PaginatedCityMuni, error) {
+	return p.paginate(params, nil)
+}
+
+func (p *cityMuniRepository) GetById(
+	_ context.Context,
+	psgcCode string,
+) (domain.CityMuni, error) {
+	return p.table.get(psgcCode, nil)
+}
+
+func (p *cityMuniRepository) GetByIds(
+	_ context.Context,
+	psgcCodes []string,
+) ([]domain.CityMuni, error) {
+	return p.table.getMany(psgcCodes), nil
+}
+
+func (p *cityMuniRepository) GetByParents(
+	_ context.Context,
+	parentCodes []string,
+) ([]domain.CityMuni, error) {
+	return p.table.children(parentCodes), nil
+}
+
+func (p *cityMuniRepository) Each(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.CityMuni) error,
+) error {
+	return p.table.each(ctx, params, nil, fn)
+}
+
+func (p *cityMuniRepository) GetAllCity(
+	_ context.Context,
+	params domain.PaginationParams,
+) (domain.PaginatedCityMuni, error) {
+	return p.paginate(params, level("City"))
+}
+
+func (p *cityMuniRepository) EachCity(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.CityMuni) error,
+) error {
+	return p.table.each(ctx, params, level("City"), fn)
+}
+
+func (p *cityMuniRepository) GetCityById(
+	_ context.Context,
+	psgcCode string,
+) (domain.CityMuni, error) {
+	return p.table.get(psgcCode, level("City"))
+}
+
+func (p *cityMuniRepository) GetAllMunicipality(
+	_ context.Context,
+	params domain.PaginationParams,
+) (domain.PaginatedCityMuni, error) {
+	return p.paginate(params, level("Mun"))
+}
+
+func (p *cityMuniRepository) EachMunicipality(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.CityMuni) error,
+) error {
+	return p.table.each(ctx, params, level("Mun"), fn)
+}
+
+func (p *cityMuniRepository) GetMunicipalityById(
+	_ context.Context,
+	psgcCode string,
+) (domain.CityMuni, error) {
+	return p.table.get(psgcCode, level("Mun"))
+}
+
+func (p *cityMuniRepository) Create(
+	_ context.Context,
+	data *domain.Masterlist,
+) error {
+	p.table.insert(domain.CityMuni{
+		PsgcCode:       data.PsgcCode,
+		ProvCode:       data.ParentCode,
+		Name:           data.Name,
+		Level:          data.Level,
+		CityClass:      data.CityClass,
+		IncomeClass:    data.IncomeClass,
+		Status:         data.Status,
+		Population2015: int(data.Population2015),
+		Population2020: int(data.Population2020),
+	})
+	return nil
+}
@@ -0,0 +1,65 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// Repositories are the in-memory repositories of the four levels
+type Repositories struct {
+	Region   domain.RegionRepository
+	Province domain.ProvinceRepository
+	CityMuni domain.CityMuniRepository
+	Barangay domain.BarangayRepository
+}
+
+// Load reads every unit from the given repositories, usually the SQLite
+// ones, into in-memory repositories
+func Load(
+	ctx context.Context,
+	regRepo domain.RegionRepository,
+	provRepo domain.ProvinceRepository,
+	cityMuniRepo domain.CityMuniRepository,
+	bgyRepo domain.BarangayRepository,
+) (*Repositories, error) {
+	regions, err := all(ctx, regRepo.Each)
+	if err != nil {
+		return nil, err
+	}
+
+	provinces, err := all(ctx, provRepo.Each)
+	if err != nil {
+		return nil, err
+	}
+
+	cityMunis, err := all(ctx, cityMuniRepo.Each)
+	if err != nil {
+		return nil, err
+	}
+
+	barangays, err := all(ctx, bgyRepo.Each)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Repositories{
+		Region:   NewRegion(regions),
+		Province: NewProvince(provinces),
+		CityMuni: NewCityMuni(cityMunis),
+		Barangay: NewBarangay(barangays),
+	}, nil
+}
+
+func all[T any](
+	ctx context.Context,
+	each func(context.Context, domain.PaginationParams, func(item T) error) error,
+) ([]T, error) {
+	items := []T{}
+	err := each(ctx, domain.PaginationParams{}, func(item T) error {
+		items = append(items, item)
+		return nil
+	})
+
+	return items, err
+}
@@ -0,0 +1,325 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/filter"
+	"github.com/Brix101/psgc-tool/internal/repository"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// dataFile is the edition tracked in the repository
+const dataFile = "../../../db/2023-10-28-data.db"
+
+// backends are the SQLite repositories over the tracked edition and the
+// in-memory ones loaded from them
+type backends struct {
+	db, mem *Repositories
+}
+
+func openBackends(t *testing.T) backends {
+	t.Helper()
+
+	if _, err := os.Stat(dataFile); err != nil {
+		t.Skipf("no data: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+dataFile+"?mode=ro&immutable=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	sqlite := &Repositories{
+		Region:   repository.NewDBRegion(db),
+		Province: repository.NewDBProvince(db),
+		CityMuni: repository.NewDBCityMuni(db),
+		Barangay: repository.NewDBBarangay(db),
+	}
+
+	mem, err := Load(context.Background(), sqlite.Region, sqlite.Province, sqlite.CityMuni, sqlite.Barangay)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return backends{db: sqlite, mem: mem}
+}
+
+// lister reads a page of a list from a backend
+type lister[T any] func(r *Repositories, params domain.PaginationParams) ([]T, domain.MetaData, error)
+
+// compareList checks that both backends answer params with the same pages,
+// the first three by cursor and the second one by number
+func compareList[T any](t *testing.T, b backends, list lister[T], params domain.PaginationParams) {
+	t.Helper()
+
+	if params.Sort == "" {
+		params.Sort = "psgc_code"
+	}
+	if params.Order == "" {
+		params.Order = domain.OrderAsc
+	}
+	if params.PerPage == 0 {
+		params.PerPage = 50
+	}
+	params.Page = 1
+
+	compare := func(what string, params domain.PaginationParams) domain.MetaData {
+		t.Helper()
+
+		dbItems, dbMeta, dbErr := list(b.db, params)
+		memItems, memMeta, memErr := list(b.mem, params)
+		if dbErr != nil || memErr != nil {
+			t.Fatalf("%s: sqlite error %v, memory error %v", what, dbErr, memErr)
+		}
+
+		if !reflect.DeepEqual(memMeta, dbMeta) {
+			t.Errorf("%s: memory metadata %+v, sqlite %+v", what, memMeta, dbMeta)
+		}
+		if !reflect.DeepEqual(memItems, dbItems) {
+			t.Errorf("%s: memory items differ from sqlite\nmemory %v\nsqlite %v", what, memItems, dbItems)
+		}
+
+		return dbMeta
+	}
+
+	meta := compare("page 1", params)
+	for i := 2; i <= 3 && meta.NextCursor != ""; i++ {
+		next := params
+		next.Cursor = meta.NextCursor
+		meta = compare(fmt.Sprintf("cursor page %d", i), next)
+	}
+
+	params.Page = 2
+	compare("page 2", params)
+}
+
+func regions(r *Repositories, params domain.PaginationParams) ([]domain.Region, domain.MetaData, error) {
+	res, err := r.Region.GetAll(context.Background(), params)
+	return res.Data, res.MetaData, err
+}
+
+func provinces(r *Repositories, params domain.PaginationParams) ([]domain.Province, domain.MetaData, error) {
+	res, err := r.Province.GetAll(context.Background(), params)
+	return res.Data, res.MetaData, err
+}
+
+func citiesMunis(r *Repositories, params domain.PaginationParams) ([]domain.CityMuni, domain.MetaData, error) {
+	res, err := r.CityMuni.GetAll(context.Background(), params)
+	return res.Data, res.MetaData, err
+}
+
+func cities(r *Repositories, params domain.PaginationParams) ([]domain.CityMuni, domain.MetaData, error) {
+	res, err := r.CityMuni.GetAllCity(context.Background(), params)
+	return res.Data, res.MetaData, err
+}
+
+func municipalities(r *Repositories, params domain.PaginationParams) ([]domain.CityMuni, domain.MetaData, error) {
+	res, err := r.CityMuni.GetAllMunicipality(context.Background(), params)
+	return res.Data, res.MetaData, err
+}
+
+func barangays(r *Repositories, params domain.PaginationParams) ([]domain.Barangay, domain.MetaData, error) {
+	res, err := r.Barangay.GetAll(context.Background(), params)
+	return res.Data, res.MetaData, err
+}
+
+// parse parses a filter expression of a test
+func parse(t *testing.T, src string, fields filter.Fields) filter.Expr {
+	t.Helper()
+
+	e, err := filter.Parse(src, fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func TestListParity(t *testing.T) {
+	b := openBackends(t)
+	unitFields := domain.CityMuniUnitFilterFields
+
+	type params = domain.PaginationParams
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T)
+	}{
+		{"regions", func(t *testing.T) { compareList(t, b, regions, params{PerPage: 5}) }},
+		{"regions by name", func(t *testing.T) {
+			compareList(t, b, regions, params{PerPage: 5, Sort: "name", Order: domain.OrderDesc})
+		}},
+		{"regions by population", func(t *testing.T) {
+			compareList(t, b, regions, params{PerPage: 5, Sort: "population_2020", Order: domain.OrderDesc})
+		}},
+		{"provinces of a region", func(t *testing.T) { compareList(t, b, provinces, params{Parent: "0100000000"}) }},
+		{"provinces by keyword", func(t *testing.T) {
+			compareList(t, b, provinces, params{PerPage: 10, Keyword: "san", Sort: "name"})
+		}},
+		{"provinces by income class", func(t *testing.T) {
+			compareList(t, b, provinces, params{PerPage: 10, IncomeClass: "1st", Sort: "population_2015"})
+		}},
+		{"cities/municipalities of a province", func(t *testing.T) {
+			compareList(t, b, citiesMunis, params{PerPage: 5, Parent: "0102800000"})
+		}},
+		{"cities/municipalities of a region", func(t *testing.T) {
+			compareList(t, b, citiesMunis, params{PerPage: 5, Parent: "1300000000", Sort: "name"})
+		}},
+		{"cities/municipalities by attributes", func(t *testing.T) {
+			compareList(t, b, citiesMunis, params{PerPage: 20, IncomeClass: "1st", Sort: "population_2020", Order: domain.OrderDesc})
+		}},
+		{"cities by class", func(t *testing.T) {
+			compareList(t, b, cities, params{PerPage: 10, CityClass: "HUC", Sort: "name"})
+		}},
+		{"cities by keyword", func(t *testing.T) {
+			compareList(t, b, cities, params{PerPage: 10, Keyword: "city of", Sort: "population_2015", Order: domain.OrderDesc})
+		}},
+		{"municipalities", func(t *testing.T) {
+			compareList(t, b, municipalities, params{PerPage: 100, Sort: "name", Order: domain.OrderDesc})
+		}},
+		{"barangays of a municipality", func(t *testing.T) {
+			compareList(t, b, barangays, params{PerPage: 7, Parent: "0102801000", Sort: "name"})
+		}},
+		{"barangays by keyword", func(t *testing.T) {
+			compareList(t, b, barangays, params{PerPage: 100, Keyword: "poblacion", Sort: "population_2020"})
+		}},
+		{"urban barangays", func(t *testing.T) {
+			compareList(t, b, barangays, params{PerPage: 100, UrbanRural: "U", Sort: "name", Order: domain.OrderDesc})
+		}},
+		{"Latin-1 names", func(t *testing.T) {
+			compareList(t, b, barangays, params{PerPage: 20, Keyword: "pi\xf1as", Sort: "name"})
+		}},
+		{"filter on the parent region", func(t *testing.T) {
+			compareList(t, b, citiesMunis, params{PerPage: 5, Filter: parse(t, "parent_code eq '1300000000'", unitFields)})
+		}},
+		{"filter out the parent province and region", func(t *testing.T) {
+			e := parse(t, "not parent_code in ('0102800000', '1300000000') and parent_code startswith '01'", unitFields)
+			compareList(t, b, citiesMunis, params{PerPage: 20, Filter: e, Sort: "name"})
+		}},
+		{"filter on the income class", func(t *testing.T) {
+			e := parse(t, "income_class eq '1ST' or income_class in ('special')", unitFields)
+			compareList(t, b, municipalities, params{PerPage: 50, Filter: e, Sort: "population_2020"})
+		}},
+		{"filter out the income class", func(t *testing.T) {
+			e := parse(t, "income_class ne '1st' and population_2015 gt 1000000", domain.ProvinceFilterFields)
+			compareList(t, b, provinces, params{PerPage: 10, Filter: e})
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+// TestFilterAgreesWithParams checks that a filter expression lists the
+// units of the query parameter it spells out
+func TestFilterAgreesWithParams(t *testing.T) {
+	b := openBackends(t)
+	unitFields := domain.CityMuniUnitFilterFields
+
+	tests := []struct {
+		name   string
+		params domain.PaginationParams
+		src    string
+	}{
+		{"parent region", domain.PaginationParams{Parent: "1300000000"}, "parent_code eq '1300000000'"},
+		{"parent province", domain.PaginationParams{Parent: "0102800000"}, "parent_code eq '0102800000'"},
+		{"income class", domain.PaginationParams{IncomeClass: "1st"}, "income_class eq '1st'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for name, r := range map[string]*Repositories{"sqlite": b.db, "memory": b.mem} {
+				byParams := tt.params
+				byParams.Page, byParams.PerPage, byParams.Sort, byParams.Order = 1, 2000, "psgc_code", domain.OrderAsc
+
+				byFilter := byParams
+				byFilter.Parent, byFilter.IncomeClass = "", ""
+				byFilter.Filter = parse(t, tt.src, unitFields)
+
+				want, _, err := citiesMunis(r, byParams)
+				if err != nil {
+					t.Fatal(err)
+				}
+				got, _, err := citiesMunis(r, byFilter)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(want) == 0 || !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: the filter lists %d units, the parameters %d", name, len(got), len(want))
+				}
+			}
+		})
+	}
+}
+
+func TestLookupParity(t *testing.T) {
+	b := openBackends(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		run  func(r *Repositories) (interface{}, error)
+	}{
+		{"region", func(r *Repositories) (interface{}, error) { return r.Region.GetById(ctx, "1300000000") }},
+		{"unknown region", func(r *Repositories) (interface{}, error) { return r.Region.GetById(ctx, "9900000000") }},
+		{"province", func(r *Repositories) (interface{}, error) { return r.Province.GetById(ctx, "0102800000") }},
+		{"city", func(r *Repositories) (interface{}, error) { return r.CityMuni.GetCityById(ctx, "1380600000") }},
+		{"municipality as a city", func(r *Repositories) (interface{}, error) { return r.CityMuni.GetCityById(ctx, "0102801000") }},
+		{"municipality", func(r *Repositories) (interface{}, error) { return r.CityMuni.GetMunicipalityById(ctx, "0102801000") }},
+		{"barangay", func(r *Repositories) (interface{}, error) { return r.Barangay.GetById(ctx, "0102801001") }},
+		{"cities/municipalities by ids", func(r *Repositories) (interface{}, error) {
+			return r.CityMuni.GetByIds(ctx, []string{"1380600000", "0000000000", "0102801000"})
+		}},
+		{"children of a region and a province", func(r *Repositories) (interface{}, error) {
+			return r.CityMuni.GetByParents(ctx, []string{"1300000000", "0102800000"})
+		}},
+		{"barangays of municipalities", func(r *Repositories) (interface{}, error) {
+			return r.Barangay.GetByParents(ctx, []string{"0102801000", "0102802000"})
+		}},
+		{"invalid cursor", func(r *Repositories) (interface{}, error) {
+			return r.Province.GetAll(ctx, domain.PaginationParams{Page: 1, PerPage: 5, Sort: "psgc_code", Order: domain.OrderAsc, Cursor: "bogus"})
+		}},
+		{"every city of a province", func(r *Repositories) (interface{}, error) {
+			lst := []domain.CityMuni{}
+			err := r.CityMuni.EachCity(ctx, domain.PaginationParams{Parent: "0300000000", Sort: "name", Order: domain.OrderAsc}, func(item domain.CityMuni) error {
+				lst = append(lst, item)
+				return nil
+			})
+			return lst, err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dbRes, dbErr := tt.run(b.db)
+			memRes, memErr := tt.run(b.mem)
+
+			if kindOf(memErr) != kindOf(dbErr) {
+				t.Fatalf("memory error %v, sqlite error %v", memErr, dbErr)
+			}
+			if !reflect.DeepEqual(memRes, dbRes) {
+				t.Errorf("memory %v\nsqlite %v", memRes, dbRes)
+			}
+		})
+	}
+}
+
+// kindOf returns the domain error err is, err itself for any other
+func kindOf(err error) error {
+	for _, target := range []error{domain.ErrNotFound, domain.ErrInvalidCursor} {
+		if errors.Is(err, target) {
+			return target
+		}
+	}
+
+	return err
+}
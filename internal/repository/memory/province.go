@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+var provinceSchema = schema[domain.Province]{
+	sortFields: domain.ProvinceSortFields,
+	code:       func(p *domain.Province) string { return p.PsgcCode },
+	name:       func(p *domain.Province) string { return p.Name },
+	parent:     func(p *domain.Province) string { return p.RegCode },
+	attributes: domain.ProvinceAttributes,
+	attribute: func(p *domain.Province, name string) string {
+		return p.IncomeClass
+	},
+	value: func(p *domain.Province, field string) interface{} {
+		switch field {
+		case "name":
+			return p.Name
+		case "reg_code":
+			return p.RegCode
+		case "income_class":
+			return p.IncomeClass
+		case "population_2015":
+			return p.Population2015
+		case "population_2020":
+			return p.Population2020
+		}
+		return p.PsgcCode
+	},
+}
+
+type provinceRepository struct {
+	table *table[domain.Province]
+}
+
+func NewProvince(items []domain.Province) domain.ProvinceRepository {
+	return &provinceRepository{table: newTable(provinceSchema, items)}
+}
+
+func (p *provinceRepository) GetAll(
+	_ context.Context,
+	params domain.PaginationParams,
+) (domain.PaginatedProvince, error) {
+	lst, metaData, err := p.table.paginate(params, nil)
+	if err != nil {
+		return domain.PaginatedProvince{}, err
+	}
+
+	return domain.PaginatedProvince{MetaData: metaData, Data: lst}, nil
+}
+
+func (p *provinceRepository) GetById(
+	_ context.Context,
+	psgcCode string,
+) (domain.Province, error) {
+	return p.table.get(psgcCode, nil)
+}
+
+func (p *provinceRepository) GetByIds(
+	_ context.Context,
+	psgcCodes []string,
+) ([]domain.Province, error) {
+	return p.table.getMany(psgcCodes), nil
+}
+
+func (p *provinceRepository) GetByParents(
+	_ context.Context,
+	parentCodes []string,
+) ([]domain.Province, error) {
+	return p.table.children(parentCodes), nil
+}
+
+func (p *provinceRepository) Each(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.Province) error,
+) error {
+	return p.table.each(ctx, params, nil, fn)
+}
+
+func (p *provinceRepository) Create(
+	_ context.Context,
+	data *domain.Masterlist,
+) error {
+	p.table.insert(domain.Province{
+		PsgcCode:       data.PsgcCode,
+		RegCode:        data.ParentCode,
+		Name:           data.Name,
+		IncomeClass:    data.IncomeClass,
+		Population2015: int(data.Population2015),
+		Population2020: int(data.Population2020),
+	})
+	return nil
+}
@@ -0,0 +1,80 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+var regionSchema = schema[domain.Region]{
+	sortFields: domain.RegionSortFields,
+	code:       func(r *domain.Region) string { return r.PsgcCode },
+	name:       func(r *domain.Region) string { return r.Name },
+	parent:     func(r *domain.Region) string { return "" },
+	value: func(r *domain.Region, field string) interface{} {
+		switch field {
+		case "name":
+			return r.Name
+		case "population_2015":
+			return r.Population2015
+		case "population_2020":
+			return r.Population2020
+		}
+		return r.PsgcCode
+	},
+}
+
+type regionRepository struct {
+	table *table[domain.Region]
+}
+
+func NewRegion(items []domain.Region) domain.RegionRepository {
+	return &regionRepository{table: newTable(regionSchema, items)}
+}
+
+func (p *regionRepository) GetAll(
+	_ context.Context,
+	params domain.PaginationParams,
+) (domain.PaginatedRegion, error) {
+	lst, metaData, err := p.table.paginate(params, nil)
+	if err != nil {
+		return domain.PaginatedRegion{}, err
+	}
+
+	return domain.PaginatedRegion{MetaData: metaData, Data: lst}, nil
+}
+
+func (p *regionRepository) GetById(
+	_ context.Context,
+	psgcCode string,
+) (domain.Region, error) {
+	return p.table.get(psgcCode, nil)
+}
+
+func (p *regionRepository) GetByIds(
+	_ context.Context,
+	psgcCodes []string,
+) ([]domain.Region, error) {
+	return p.table.getMany(psgcCodes), nil
+}
+
+func (p *regionRepository) Each(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item domain.Region) error,
+) error {
+	return p.table.each(ctx, params, nil, fn)
+}
+
+func (p *regionRepository) Create(
+	_ context.Context,
+	data *domain.Masterlist,
+) error {
+	p.table.insert(domain.Region{
+		PsgcCode:       data.PsgcCode,
+		Name:           data.Name,
+		Population2015: int(data.Population2015),
+		Population2020: int(data.Population2020),
+	})
+	return nil
+}
@@ -0,0 +1,390 @@
+// Package memory implements the geographic repositories over the whole
+// dataset held in memory. The PSGC is small enough, about 43k units, to load
+// at startup, lookups then don't touch SQLite at all. The repositories are
+// also usable as fakes, they start from any list of items.
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/filter"
+	"github.com/Brix101/psgc-tool/internal/repository"
+)
+
+// schema describes how a table reads its items
+type schema[T any] struct {
+	sortFields []string
+	code       func(item *T) string
+	name       func(item *T) string
+	// parent is the code the item is listed under with ?parent=, empty for
+	// items without one
+	parent func(item *T) string
+	// value returns a sort or filter field of the item, an int or a string
+	value func(item *T, field string) interface{}
+	// attributes are the attributes the items can be filtered on and
+	// attribute returns one of them
+	attributes []domain.Attribute
+	attribute  func(item *T, name string) string
+}
+
+// table is the in-memory counterpart of repository.Table. It pages, sorts
+// and filters with the same rules, so both backends answer a request with
+// the same page and metadata.
+type table[T any] struct {
+	schema schema[T]
+
+	mu    sync.Mutex
+	items []T
+	// idx is rebuilt on the first read after an insert
+	idx *index[T]
+}
+
+// index is an immutable view of a table's items
+type index[T any] struct {
+	items    []T // in psgc_code order
+	byCode   map[string]int
+	byParent map[string][]int // in psgc_code order
+	// names are the lowercased names, for keyword search
+	names []string
+	// sorted holds, for every sort field, the positions of the items
+	// ascending by that field then psgc_code
+	sorted map[string][]int
+}
+
+func newTable[T any](s schema[T], items []T) *table[T] {
+	return &table[T]{schema: s, items: slices.Clone(items)}
+}
+
+func (t *table[T]) view() *index[T] {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.idx == nil {
+		t.idx = t.build()
+	}
+
+	return t.idx
+}
+
+func (t *table[T]) build() *index[T] {
+	items := slices.Clone(t.items)
+	slices.SortFunc(items, func(a, b T) int {
+		return strings.Compare(t.schema.code(&a), t.schema.code(&b))
+	})
+
+	idx := &index[T]{
+		items:    items,
+		byCode:   make(map[string]int, len(items)),
+		byParent: map[string][]int{},
+		names:    make([]string, len(items)),
+		sorted:   map[string][]int{},
+	}
+
+	for i := range items {
+		item := &items[i]
+
+		idx.byCode[t.schema.code(item)] = i
+		idx.names[i] = strings.ToLower(t.schema.name(item))
+
+		if parent := t.schema.parent(item); parent != "" {
+			idx.byParent[parent] = append(idx.byParent[parent], i)
+		}
+	}
+
+	for _, field := range t.schema.sortFields {
+		positions := make([]int, len(items))
+		for i := range positions {
+			positions[i] = i
+		}
+
+		if field != "psgc_code" {
+			t.sortPositions(idx, positions, field)
+		}
+		idx.sorted[field] = positions
+	}
+
+	return idx
+}
+
+func (t *table[T]) sortPositions(idx *index[T], positions []int, field string) {
+	slices.SortStableFunc(positions, func(a, b int) int {
+		// positions start in psgc_code order, the stable sort keeps it as
+		// the tie-breaker
+		return compareValues(
+			t.schema.value(&idx.items[a], field),
+			t.schema.value(&idx.items[b], field),
+		)
+	})
+}
+
+// insert adds or replaces an item by psgc_code
+func (t *table[T]) insert(item T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	code := t.schema.code(&item)
+	for i := range t.items {
+		if t.schema.code(&t.items[i]) == code {
+			t.items[i] = item
+			t.idx = nil
+			return
+		}
+	}
+
+	t.items = append(t.items, item)
+	t.idx = nil
+}
+
+func (t *table[T]) get(psgcCode string, filter func(item *T) bool) (T, error) {
+	idx := t.view()
+
+	i, ok := idx.byCode[psgcCode]
+	if !ok || (filter != nil && !filter(&idx.items[i])) {
+		var zero T
+		return zero, domain.ErrNotFound
+	}
+
+	return idx.items[i], nil
+}
+
+// getMany returns the items with the given codes in psgc_code order, like
+// repository.Table.All
+func (t *table[T]) getMany(psgcCodes []string) []T {
+	idx := t.view()
+
+	positions := []int{}
+	for _, code := range psgcCodes {
+		if i, ok := idx.byCode[code]; ok {
+			positions = append(positions, i)
+		}
+	}
+
+	return idx.collect(positions)
+}
+
+// children returns the items listed under any of the parent codes in
+// psgc_code order
+func (t *table[T]) children(parentCodes []string) []T {
+	idx := t.view()
+
+	positions := []int{}
+	for _, code := range parentCodes {
+		positions = append(positions, idx.byParent[code]...)
+	}
+
+	return idx.collect(positions)
+}
+
+// collect returns the items at the positions once each, positions follow
+// psgc_code order
+func (idx *index[T]) collect(positions []int) []T {
+	slices.Sort(positions)
+	positions = slices.Compact(positions)
+
+	lst := make([]T, len(positions))
+	for n, i := range positions {
+		lst[n] = idx.items[i]
+	}
+
+	return lst
+}
+
+// scan calls fn with the items matching params and filter, in the order of
+// the sort field. order is the direction to walk in.
+func (t *table[T]) scan(
+	idx *index[T],
+	params domain.PaginationParams,
+	field, order string,
+	filter func(item *T) bool,
+	fn func(item *T) bool,
+) {
+	var positions []int
+	if params.Parent != "" {
+		positions = slices.Clone(idx.byParent[params.Parent])
+		if field != "psgc_code" {
+			t.sortPositions(idx, positions, field)
+		}
+	} else {
+		positions = idx.sorted[field]
+	}
+
+	keyword := strings.ToLower(params.Keyword)
+
+	for n := range positions {
+		i := positions[n]
+		if order == domain.OrderDesc {
+			i = positions[len(positions)-1-n]
+		}
+
+		item := &idx.items[i]
+		if keyword != "" &&
+			!strings.Contains(idx.names[i], keyword) &&
+			!strings.Contains(t.schema.code(item), keyword) {
+			continue
+		}
+		if !t.matchAttributes(item, params) || !t.matchExpression(item, params) {
+			continue
+		}
+		if filter != nil && !filter(item) {
+			continue
+		}
+
+		if !fn(item) {
+			return
+		}
+	}
+}
+
+// matchAttributes reports whether the item has the attribute values of
+// params, with the same rules as repository.AttributeFilters
+func (t *table[T]) matchAttributes(item *T, params domain.PaginationParams) bool {
+	for _, attribute := range t.schema.attributes {
+		want := params.AttributeValue(attribute.Name)
+		if want != "" && !domain.MatchAttribute(attribute.Name, want, t.schema.attribute(item, attribute.Name)) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchExpression reports whether the item matches the filter expression of
+// params, there is none to match when it is nil
+func (t *table[T]) matchExpression(item *T, params domain.PaginationParams) bool {
+	if params.Filter == nil {
+		return true
+	}
+
+	return filter.Eval(params.Filter, func(column string) interface{} {
+		return t.schema.value(item, column)
+	})
+}
+
+// paginate returns a page of the items matching params and filter
+func (t *table[T]) paginate(
+	params domain.PaginationParams,
+	filter func(item *T) bool,
+) ([]T, domain.MetaData, error) {
+	idx := t.view()
+
+	queryParams := params
+	offset := (params.Page - 1) * params.PerPage
+
+	var cursor *domain.Cursor
+	if params.Cursor != "" {
+		c, err := domain.DecodeCursor(params.Cursor)
+		if err != nil {
+			return nil, domain.MetaData{}, err
+		}
+
+		queryParams = repository.CursorParams(params, c)
+		cursor, offset = &c, 0
+	}
+
+	field := t.sortColumn(queryParams.Sort)
+
+	totalItems := 0
+	lst := []T{}
+	t.scan(idx, queryParams, field, queryParams.Order, filter, func(item *T) bool {
+		// The total ignores the cursor, like the count query
+		totalItems++
+
+		if cursor != nil && !t.pastCursor(item, *cursor, field, queryParams.Order) {
+			return true
+		}
+		if offset > 0 {
+			offset--
+			return true
+		}
+
+		// One extra item tells whether there is a next page
+		if len(lst) <= params.PerPage {
+			lst = append(lst, *item)
+		}
+		return true
+	})
+
+	lst, links := repository.KeysetPage(lst, params, cursor, t.sortValue)
+
+	return lst, repository.NewMetaData(params, cursor, totalItems, len(lst), links), nil
+}
+
+// each calls fn for every item matching params and filter, unpaginated
+func (t *table[T]) each(
+	ctx context.Context,
+	params domain.PaginationParams,
+	filter func(item *T) bool,
+	fn func(item T) error,
+) error {
+	idx := t.view()
+
+	var err error
+	t.scan(idx, params, t.sortColumn(params.Sort), params.Order, filter, func(item *T) bool {
+		if err = ctx.Err(); err != nil {
+			return false
+		}
+
+		err = fn(*item)
+		return err == nil
+	})
+
+	return err
+}
+
+// pastCursor reports whether the item comes after the cursor row when
+// walking in order
+func (t *table[T]) pastCursor(item *T, cursor domain.Cursor, field, order string) bool {
+	c := strings.Compare(t.schema.code(item), cursor.PsgcCode)
+	if field != "psgc_code" {
+		if v := compareValues(t.schema.value(item, field), cursor.Value); v != 0 {
+			c = v
+		}
+	}
+
+	if order == domain.OrderDesc {
+		return c < 0
+	}
+	return c > 0
+}
+
+func (t *table[T]) sortColumn(sort string) string {
+	if slices.Contains(t.schema.sortFields, sort) {
+		return sort
+	}
+
+	return "psgc_code"
+}
+
+func (t *table[T]) sortValue(item T, field string) (interface{}, string) {
+	return t.schema.value(&item, field), t.schema.code(&item)
+}
+
+// compareValues compares two sort values. Strings compare case-insensitively
+// like the NOCASE name columns, numbers by value whatever their type, a
+// cursor's numbers are int64.
+func compareValues(a, b interface{}) int {
+	if sa, ok := a.(string); ok {
+		sb, _ := b.(string)
+		return strings.Compare(strings.ToLower(sa), strings.ToLower(sb))
+	}
+
+	return cmp.Compare(toFloat(a), toFloat(b))
+}
+
+func toFloat(v interface{}) float64 {
+	switch n := v.(type) {
+	case int:
+		return float64(n)
+	case int64:
+		return float64(n)
+	case float64:
+		return n
+	}
+
+	return 0
+}
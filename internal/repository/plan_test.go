@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	psgctool "github.com/Brix101/psgc-tool"
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/pressly/goose/v3"
+)
+
+// newMigratedDB returns an empty in-memory database with the schema of the
+// migrations
+func newMigratedDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:?_foreign_keys=on")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every connection to :memory: is its own database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	goose.SetBaseFS(psgctool.EmbedMigrations)
+	goose.SetLogger(goose.NopLogger())
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := goose.Up(db, "migrations"); err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// recordingConn remembers the queries run through it
+type recordingConn struct {
+	*sql.DB
+	queries []recordedQuery
+}
+
+type recordedQuery struct {
+	query string
+	args  []interface{}
+}
+
+func (c *recordingConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	c.queries = append(c.queries, recordedQuery{query, args})
+	return c.DB.QueryContext(ctx, query, args...)
+}
+
+// queryPlan returns the details of the EXPLAIN QUERY PLAN rows of a query
+func queryPlan(t *testing.T, db *sql.DB, q recordedQuery) []string {
+	t.Helper()
+
+	rows, err := db.Query("EXPLAIN QUERY PLAN "+q.query, q.args...)
+	if err != nil {
+		t.Fatalf("explaining %s: %v", q.query, err)
+	}
+	defer rows.Close()
+
+	details := []string{}
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			t.Fatal(err)
+		}
+		details = append(details, detail)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	return details
+}
+
+// TestHierarchyFiltersUseIndexes checks the plans of the list queries the
+// repositories run for the parent, level and name filters of the hierarchy:
+// each must search an index and none may sort in a temporary b-tree.
+func TestHierarchyFiltersUseIndexes(t *testing.T) {
+	db := newMigratedDB(t)
+	conn := &recordingConn{DB: db}
+	ctx := context.Background()
+
+	provinces := &dbProvinceRepository{table: NewTable(conn, "province", provinceColumns, domain.ProvinceSortFields)}
+	citiesMunis := &dbCityMuniRepository{table: NewTable(conn, "city_muni", cityMuniColumns, domain.CityMuniSortFields)}
+	barangays := &dbBarangayRepository{table: NewTable(conn, "barangay", barangayColumns, domain.BarangaySortFields)}
+	hierarchy := &dbHierarchyRepository{table: NewTable(conn, "geo_unit", geoUnitColumns, domain.GeoUnitSortFields)}
+
+	page := func(sort, parent string) domain.PaginationParams {
+		return domain.PaginationParams{Page: 1, PerPage: 100, Sort: sort, Order: domain.OrderAsc, Parent: parent}
+	}
+
+	tests := []struct {
+		name string
+		run  func() error
+		// plan matches the detail of the row that reads the table
+		plan string
+	}{
+		{
+			name: "provinces of a region",
+			run: func() error {
+				_, err := provinces.GetAll(ctx, page("psgc_code", "0100000000"))
+				return err
+			},
+			plan: `SEARCH province USING (COVERING )?INDEX province_reg_code\w*_idx \(reg_code=\?`,
+		},
+		{
+			name: "cities/municipalities of a province",
+			run: func() error {
+				_, err := citiesMunis.GetAll(ctx, page("psgc_code", "0102800000"))
+				return err
+			},
+			plan: `SEARCH city_muni USING (COVERING )?INDEX city_muni_prov_code\w*_idx \(prov_code=\?`,
+		},
+		{
+			name: "barangays of a city/municipality",
+			run: func() error {
+				_, err := barangays.GetAll(ctx, page("psgc_code", "0102801000"))
+				return err
+			},
+			plan: `SEARCH barangay USING (COVERING )?INDEX barangay_citmun_code\w*_idx \(citmun_code=\?`,
+		},
+		{
+			name: "cities",
+			run: func() error {
+				_, err := citiesMunis.GetAllCity(ctx, page("psgc_code", ""))
+				return err
+			},
+			plan: `SEARCH city_muni USING (COVERING )?INDEX city_muni_level_idx \(level=\?`,
+		},
+		{
+			name: "units of a level",
+			run: func() error {
+				_, err := hierarchy.GetAll(ctx, domain.LevelMunicipality, page("psgc_code", ""))
+				return err
+			},
+			plan: `SEARCH geo_unit USING (COVERING )?INDEX geo_unit_level_idx \(level=\?`,
+		},
+		{
+			name: "children of a unit",
+			run: func() error {
+				_, err := hierarchy.Children(ctx, "0102800000", page("psgc_code", ""))
+				return err
+			},
+			plan: `SEARCH geo_unit USING (COVERING )?INDEX geo_unit_parent_code_idx \(parent_code=\?`,
+		},
+		{
+			name: "barangays of a city/municipality by name",
+			run: func() error {
+				_, err := barangays.GetAll(ctx, page("name", "0102801000"))
+				return err
+			},
+			plan: `SEARCH barangay USING (COVERING )?INDEX barangay_citmun_code_name_idx \(citmun_code=\?`,
+		},
+		{
+			name: "provinces by name",
+			run: func() error {
+				_, err := provinces.GetAll(ctx, page("name", ""))
+				return err
+			},
+			plan: `SCAN province USING (COVERING )?INDEX province_name_idx`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn.queries = nil
+			if err := tt.run(); err != nil {
+				t.Fatal(err)
+			}
+			if len(conn.queries) == 0 {
+				t.Fatal("no query was run")
+			}
+
+			// The page query, the count runs through QueryRowContext
+			plan := queryPlan(t, db, conn.queries[0])
+			if !slices.ContainsFunc(plan, regexp.MustCompile(tt.plan).MatchString) {
+				t.Errorf("plan %q doesn't match %s", plan, tt.plan)
+			}
+			if slices.ContainsFunc(plan, func(detail string) bool { return strings.Contains(detail, "TEMP B-TREE") }) {
+				t.Errorf("plan %q sorts in a temporary b-tree", plan)
+			}
+		})
+	}
+}
+
+// editionFile is the edition tracked in the repository
+const editionFile = "../../db/2023-10-28-data.db"
+
+// openEdition opens the tracked edition read-only, it skips the test when
+// the file is missing
+func openEdition(tb testing.TB) *sql.DB {
+	tb.Helper()
+
+	if _, err := os.Stat(editionFile); err != nil {
+		tb.Skipf("no data: %v", err)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+editionFile+"?mode=ro&immutable=1")
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// BenchmarkHierarchyFilters runs the list queries of the parent, level and
+// name filters over the tracked edition. The keyword search is a baseline,
+// its leading wildcard can't use an index.
+func BenchmarkHierarchyFilters(b *testing.B) {
+	db := openEdition(b)
+	ctx := context.Background()
+
+	provinces, citiesMunis := NewDBProvince(db), NewDBCityMuni(db)
+	barangays, hierarchy := NewDBBarangay(db), NewDBHierarchy(db)
+
+	page := func(sort, parent string) domain.PaginationParams {
+		return domain.PaginationParams{Page: 1, PerPage: 100, Sort: sort, Order: domain.OrderAsc, Parent: parent}
+	}
+
+	benchmarks := []struct {
+		name string
+		run  func() error
+	}{
+		{"provinces of a region", func() error {
+			_, err := provinces.GetAll(ctx, page("psgc_code", "0100000000"))
+			return err
+		}},
+		{"cities/municipalities of a province", func() error {
+			_, err := citiesMunis.GetAll(ctx, page("psgc_code", "0102800000"))
+			return err
+		}},
+		{"barangays of a city/municipality", func() error {
+			_, err := barangays.GetAll(ctx, page("psgc_code", "0102801000"))
+			return err
+		}},
+		{"barangays of a city/municipality by name", func() error {
+			_, err := barangays.GetAll(ctx, page("name", "0102801000"))
+			return err
+		}},
+		{"cities", func() error {
+			_, err := citiesMunis.GetAllCity(ctx, page("psgc_code", ""))
+			return err
+		}},
+		{"barangays by name", func() error {
+			_, err := barangays.GetAll(ctx, page("name", ""))
+			return err
+		}},
+		{"units of a level", func() error {
+			_, err := hierarchy.GetAll(ctx, domain.LevelMunicipality, page("psgc_code", ""))
+			return err
+		}},
+		{"children of a unit", func() error {
+			_, err := hierarchy.Children(ctx, "0102800000", page("psgc_code", ""))
+			return err
+		}},
+		{"barangays by keyword", func() error {
+			params := page("psgc_code", "")
+			params.Keyword = "poblacion"
+			_, err := barangays.GetAll(ctx, params)
+			return err
+		}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := bm.run(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// queryBuilder builds the SELECT and COUNT queries over one table. Filters
+// added with Where and Keyword are shared by both, so the totals in the
+// pagination metadata always describe the filtered rows. Every value is
+// passed as a parameter.
+type queryBuilder struct {
+	table      string
+	columns    string
+	conditions []string
+	args       []interface{}
+}
+
+func newQueryBuilder(table, columns string) *queryBuilder {
+	return &queryBuilder{table: table, columns: columns}
+}
+
+// Where adds a condition, its ? placeholders are bound to args
+func (q *queryBuilder) Where(condition string, args ...interface{}) *queryBuilder {
+	q.conditions = append(q.conditions, condition)
+	q.args = append(q.args, args...)
+	return q
+}
+
+// Keyword matches rows where any of the columns contains the keyword, it is
+// a no-op for an empty keyword. LIKE ignores ASCII case on its own, so the
+// columns aren't wrapped in LOWER(). The pattern starts with a wildcard, so
+// no index can serve it: it checks the rows the other conditions select.
+func (q *queryBuilder) Keyword(keyword string, columns ...string) *queryBuilder {
+	if keyword == "" {
+		return q
+	}
+
+	matches := make([]string, len(columns))
+	args := make([]interface{}, len(columns))
+	for i, column := range columns {
+		matches[i] = column + " LIKE '%' || ? || '%'"
+		args[i] = keyword
+	}
+
+	return q.Where("("+strings.Join(matches, " OR ")+")", args...)
+}
+
+func (q *queryBuilder) where(extra ...string) string {
+	conditions := append(append([]string{}, q.conditions...), extra...)
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// Select returns the query for every row matching the filters
+func (q *queryBuilder) Select() (string, []interface{}) {
+	return "SELECT " + q.columns + " FROM " + q.table + q.where(), q.args
+}
+
+// Count returns the query for the number of rows matching the filters
+func (q *queryBuilder) Count() (string, []interface{}) {
+	return "SELECT COUNT(*) FROM " + q.table + q.where(), q.args
+}
+
+// Page returns the query for one page of rows. With a cursor in params the
+// page continues from the cursor row instead of skipping rows with OFFSET,
+// so deep pages stay fast and don't shift when rows are added. The cursor
+// condition only applies to the page, never to Count.
+//
+// The query reads one row more than per_page, it tells whether there is a
+// next page and is trimmed by KeysetPage.
+func (q *queryBuilder) Page(
+	params domain.PaginationParams,
+	sortFields []string,
+) (string, []interface{}, *domain.Cursor, error) {
+	args := append([]interface{}{}, q.args...)
+	offset := (params.Page - 1) * params.PerPage
+
+	var keyset []string
+	var cursor *domain.Cursor
+	if params.Cursor != "" {
+		c, err := domain.DecodeCursor(params.Cursor)
+		if err != nil {
+			return "", nil, nil, err
+		}
+
+		condition, keysetArgs := keysetCondition(c, sortFields)
+		keyset = append(keyset, condition)
+		args = append(args, keysetArgs...)
+		params = CursorParams(params, c)
+		cursor, offset = &c, 0
+	}
+
+	query := "SELECT " + q.columns + " FROM " + q.table + q.where(keyset...) +
+		orderBy(params, sortFields) +
+		" LIMIT ? OFFSET ?"
+	args = append(args, params.PerPage+1, offset)
+
+	return query, args, cursor, nil
+}
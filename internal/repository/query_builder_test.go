@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestQueryBuilderSharesConditions(t *testing.T) {
+	q := newQueryBuilder("city_muni", "psgc_code, name").
+		Where("prov_code = ?", "0102800000").
+		Keyword("san", "psgc_code", "name")
+
+	wantWhere := " WHERE prov_code = ? AND (psgc_code LIKE '%' || ? || '%' OR name LIKE '%' || ? || '%')"
+	wantArgs := []interface{}{"0102800000", "san", "san"}
+
+	selectQuery, selectArgs := q.Select()
+	countQuery, countArgs := q.Count()
+
+	if want := "SELECT psgc_code, name FROM city_muni" + wantWhere; selectQuery != want {
+		t.Errorf("Select() = %q, want %q", selectQuery, want)
+	}
+	if want := "SELECT COUNT(*) FROM city_muni" + wantWhere; countQuery != want {
+		t.Errorf("Count() = %q, want %q", countQuery, want)
+	}
+	if !reflect.DeepEqual(selectArgs, wantArgs) || !reflect.DeepEqual(countArgs, wantArgs) {
+		t.Errorf("args = %v and %v, want %v", selectArgs, countArgs, wantArgs)
+	}
+
+	// A keyset page adds its condition to the page only
+	cursor := domain.Cursor{Sort: "name", Order: domain.OrderAsc, Value: "Bacarra", PsgcCode: "0102802000"}
+	params := domain.PaginationParams{Page: 1, PerPage: 10, Sort: "name", Order: domain.OrderAsc, Cursor: cursor.Encode()}
+	pageQuery, pageArgs, _, err := q.Page(params, []string{"psgc_code", "name"})
+	if err != nil {
+		t.Fatalf("Page: %v", err)
+	}
+
+	if want := wantWhere + " AND (name, psgc_code) > (?, ?) ORDER BY name ASC, psgc_code ASC LIMIT ? OFFSET ?"; !strings.HasSuffix(pageQuery, want) {
+		t.Errorf("Page() = %q, want the suffix %q", pageQuery, want)
+	}
+	if want := append(slices.Clone(wantArgs), "Bacarra", "0102802000", 11, 0); !reflect.DeepEqual(pageArgs, want) {
+		t.Errorf("Page() args = %v, want %v", pageArgs, want)
+	}
+	if _, countArgs := q.Count(); !reflect.DeepEqual(countArgs, wantArgs) {
+		t.Errorf("Count() args after Page = %v, want %v", countArgs, wantArgs)
+	}
+}
+
+type testRow struct {
+	PsgcCode   string
+	ParentCode string
+	Name       string
+	Population int
+}
+
+var testColumns = []Column[testRow]{
+	{"psgc_code", func(r *testRow) interface{} { return &r.PsgcCode }},
+	{"parent_code", func(r *testRow) interface{} { return &r.ParentCode }},
+	{"name", func(r *testRow) interface{} { return &r.Name }},
+	{"population", func(r *testRow) interface{} { return &r.Population }},
+}
+
+// newTestTable creates a table of 50 rows with repeated names and
+// populations, so every sort has ties broken by psgc_code
+func newTestTable(t *testing.T) (*Table[testRow], []testRow) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	_, err = db.Exec(`CREATE TABLE unit (
+		psgc_code TEXT PRIMARY KEY,
+		parent_code TEXT NOT NULL,
+		name TEXT COLLATE NOCASE,
+		population INTEGER NOT NULL
+	)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := NewTable(db, "unit", testColumns, []string{"psgc_code", "name", "population"})
+	names := []string{"San Jose", "Santa Cruz", "Bangui", "san isidro", "Pagudpud"}
+
+	rows := []testRow{}
+	for i := 0; i < 50; i++ {
+		row := testRow{
+			PsgcCode:   fmt.Sprintf("01%02d000000", i),
+			ParentCode: fmt.Sprintf("%d", i%3),
+			Name:       names[i%len(names)],
+			Population: (i % 7) * 1000,
+		}
+		if err := table.Insert(context.Background(), row); err != nil {
+			t.Fatal(err)
+		}
+		rows = append(rows, row)
+	}
+
+	return table, rows
+}
+
+func TestPaginateCountMatchesPages(t *testing.T) {
+	table, rows := newTestTable(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		params  domain.PaginationParams
+		filters []Filter
+		want    func(r testRow) bool
+	}{
+		{
+			name:   "everything",
+			params: domain.PaginationParams{Sort: "psgc_code", Order: domain.OrderAsc},
+			want:   func(testRow) bool { return true },
+		},
+		{
+			name:    "filter and keyword",
+			params:  domain.PaginationParams{Sort: "name", Order: domain.OrderAsc, Keyword: "san"},
+			filters: []Filter{Eq("parent_code", "1")},
+			want: func(r testRow) bool {
+				return r.ParentCode == "1" && strings.Contains(strings.ToLower(r.Name), "san")
+			},
+		},
+		{
+			name:    "descending with ties",
+			params:  domain.PaginationParams{Sort: "population", Order: domain.OrderDesc},
+			filters: []Filter{In("parent_code", []string{"0", "2"})},
+			want:    func(r testRow) bool { return r.ParentCode != "1" },
+		},
+		{
+			name:   "nothing",
+			params: domain.PaginationParams{Sort: "name", Order: domain.OrderDesc, Keyword: "zzz"},
+			want:   func(testRow) bool { return false },
+		},
+	}
+
+	for _, tt := range tests {
+		want := []string{}
+		for _, row := range rows {
+			if tt.want(row) {
+				want = append(want, row.PsgcCode)
+			}
+		}
+
+		for _, perPage := range []int{1, 4, 7, 100} {
+			t.Run(fmt.Sprintf("%s by pages of %d", tt.name, perPage), func(t *testing.T) {
+				params := tt.params
+				params.PerPage = perPage
+
+				// The same rows come out in the same order page by page and
+				// cursor by cursor
+				var byPage []string
+				for _, mode := range []string{"page", "cursor"} {
+					got := []string{}
+					params.Page, params.Cursor = 1, ""
+					for {
+						lst, meta, err := table.Paginate(ctx, params, tt.filters...)
+						if err != nil {
+							t.Fatalf("%s: Paginate: %v", mode, err)
+						}
+						if meta.TotalItems != len(want) {
+							t.Fatalf("%s: total_items = %d, want %d", mode, meta.TotalItems, len(want))
+						}
+						for _, row := range lst {
+							got = append(got, row.PsgcCode)
+						}
+
+						if !meta.HasNext {
+							break
+						}
+						if len(got) > len(rows) {
+							t.Fatalf("%s: more rows than the table has, pages repeat", mode)
+						}
+						if mode == "page" {
+							params.Page++
+						} else {
+							params.Cursor = meta.NextCursor
+						}
+					}
+
+					gotSet := slices.Clone(got)
+					slices.Sort(gotSet)
+					if len(got) != len(want) || !slices.Equal(gotSet, want) {
+						t.Errorf("%s: got %v, want the rows %v", mode, got, want)
+					}
+					if mode == "page" {
+						byPage = got
+					} else if !slices.Equal(got, byPage) {
+						t.Errorf("cursors read %v, pages read %v", got, byPage)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// orderBy builds the ORDER BY clause for a paginated query. The sort field is
+// checked against the resource's sort fields before it is written into the
+// query, anything else falls back to the official psgc_code order. psgc_code
+// is always the last key so rows with equal values keep a stable order.
+func orderBy(params domain.PaginationParams, sortFields []string) string {
+	column := sortColumn(params.Sort, sortFields)
+
+	direction := "ASC"
+	if params.Order == domain.OrderDesc {
+		direction = "DESC"
+	}
+
+	if column == "psgc_code" {
+		return fmt.Sprintf(" ORDER BY psgc_code %s", direction)
+	}
+
+	return fmt.Sprintf(" ORDER BY %s %s, psgc_code %s", column, direction, direction)
+}
+
+// sortColumn returns the sort field if it is one of sortFields, psgc_code
+// otherwise.
+func sortColumn(sort string, sortFields []string) string {
+	for _, field := range sortFields {
+		if field == sort {
+			return field
+		}
+	}
+
+	return "psgc_code"
+}
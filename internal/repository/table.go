@@ -0,0 +1,372 @@
+package repository
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Column maps a table column to a field of T. Field returns a pointer to
+// the field, it is used both to scan into and to read from an item. The
+// first column is the psgc_code primary key.
+type Column[T any] struct {
+	Name  string
+	Field func(item *T) interface{}
+}
+
+// nullString scans NULL into an empty string and writes an empty string as
+// NULL, for optional parent codes
+type nullString struct{ s *string }
+
+func (n nullString) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*n.s = ""
+	case string:
+		*n.s = v
+	case []byte:
+		*n.s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a string", value)
+	}
+	return nil
+}
+
+func (n nullString) Value() (driver.Value, error) {
+	if *n.s == "" {
+		return nil, nil
+	}
+	return *n.s, nil
+}
+
+// Filter is a parameterized condition on a table
+type Filter struct {
+	Condition string
+	Args      []interface{}
+}
+
+// Eq filters the rows where column equals value
+func Eq(column string, value interface{}) Filter {
+	return Filter{Condition: column + " = ?", Args: []interface{}{value}}
+}
+
+// In filters the rows where column is one of values, no row matches an
+// empty list
+func In(column string, values []string) Filter {
+	if len(values) == 0 {
+		return Filter{Condition: "0"}
+	}
+
+	args := make([]interface{}, len(values))
+	for i, value := range values {
+		args[i] = value
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")
+	return Filter{Condition: column + " IN (" + placeholders + ")", Args: args}
+}
+
+// Or filters the rows matching any of the filters
+func Or(filters ...Filter) Filter {
+	if len(filters) == 0 {
+		return Filter{Condition: "0"}
+	}
+
+	conditions := []string{}
+	args := []interface{}{}
+	for _, filter := range filters {
+		conditions = append(conditions, "("+filter.Condition+")")
+		args = append(args, filter.Args...)
+	}
+
+	return Filter{Condition: "(" + strings.Join(conditions, " OR ") + ")", Args: args}
+}
+
+// ParentFilter limits a list to the children of parentCode, it returns no
+// filter when parentCode is empty. column is the table's indexed parent code.
+func ParentFilter(column, parentCode string) []Filter {
+	if parentCode == "" {
+		return nil
+	}
+
+	return []Filter{Eq(column, parentCode)}
+}
+
+// AttributeFilters limits a list to the attribute values of params, of the
+// attributes the table has. Income classes also match their footnoted
+// values, like domain.MatchAttribute.
+func AttributeFilters(params domain.PaginationParams, attributes []domain.Attribute) []Filter {
+	filters := []Filter{}
+	for _, attribute := range attributes {
+		value := params.AttributeValue(attribute.Name)
+		if value == "" {
+			continue
+		}
+
+		if attribute.Name == domain.IncomeClass.Name {
+			filters = append(filters, Filter{
+				Condition: "(income_class = ? OR income_class GLOB ?)",
+				Args:      []interface{}{value, value + "[* ]*"},
+			})
+			continue
+		}
+
+		filters = append(filters, Eq(attribute.Name, value))
+	}
+
+	return filters
+}
+
+// Table is a repository over one PSGC table. It holds the column metadata
+// and does the scanning, filtering, sorting and pagination for the
+// domain repositories, which are thin wrappers over it.
+//
+// Every PSGC table has a psgc_code primary key and a name, the keyword
+// filter searches both.
+type Table[T any] struct {
+	conn       Connection
+	tracer     trace.Tracer
+	name       string
+	columns    []Column[T]
+	sortFields []string
+}
+
+func NewTable[T any](
+	conn Connection,
+	name string,
+	columns []Column[T],
+	sortFields []string,
+) *Table[T] {
+	return &Table[T]{
+		conn:       conn,
+		tracer:     otel.Tracer("db:sqlite3:" + name),
+		name:       name,
+		columns:    columns,
+		sortFields: sortFields,
+	}
+}
+
+func (t *Table[T]) columnNames() string {
+	names := make([]string, len(t.columns))
+	for i, column := range t.columns {
+		names[i] = column.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func (t *Table[T]) query(filters []Filter) *queryBuilder {
+	q := newQueryBuilder(t.name, t.columnNames())
+	for _, filter := range filters {
+		q.Where(filter.Condition, filter.Args...)
+	}
+	return q
+}
+
+func (t *Table[T]) fetch(
+	ctx context.Context,
+	query string,
+	args ...interface{},
+) ([]T, error) {
+	var lst []T
+	err := t.each(ctx, query, args, func(item T) error {
+		lst = append(lst, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lst, nil
+}
+
+// each scans the rows of the query one at a time, it stops at the first
+// error returned by fn
+func (t *Table[T]) each(
+	ctx context.Context,
+	query string,
+	args []interface{},
+	fn func(item T) error,
+) error {
+	ctx, span := spanWithQuery(ctx, t.tracer, query)
+	defer span.End()
+
+	rows, err := t.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		span.SetStatus(codes.Error, "failed querying "+t.name)
+		span.RecordError(err)
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item T
+		dest := make([]interface{}, len(t.columns))
+		for i, column := range t.columns {
+			dest[i] = column.Field(&item)
+		}
+
+		if err := rows.Scan(dest...); err != nil {
+			return err
+		}
+
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// sortValue returns the value of the sort field of an item and its
+// psgc_code, for building cursors
+func (t *Table[T]) sortValue(item T, field string) (interface{}, string) {
+	value := func(name string) interface{} {
+		for _, column := range t.columns {
+			if column.Name != name {
+				continue
+			}
+
+			switch v := column.Field(&item).(type) {
+			case *string:
+				return *v
+			case *int:
+				return *v
+			}
+		}
+		return nil
+	}
+
+	psgcCode, _ := value("psgc_code").(string)
+	return value(field), psgcCode
+}
+
+// Find returns every row matching the filters
+func (t *Table[T]) Find(ctx context.Context, filters ...Filter) ([]T, error) {
+	query, args := t.query(filters).Select()
+	return t.fetch(ctx, query, args...)
+}
+
+// All returns every row matching the filters in psgc_code order
+func (t *Table[T]) All(ctx context.Context, filters ...Filter) ([]T, error) {
+	lst := []T{}
+	err := t.Each(ctx, domain.PaginationParams{}, func(item T) error {
+		lst = append(lst, item)
+		return nil
+	}, filters...)
+
+	return lst, err
+}
+
+// Get returns the first row matching the filters, or domain.ErrNotFound
+func (t *Table[T]) Get(ctx context.Context, filters ...Filter) (T, error) {
+	lst, err := t.Find(ctx, filters...)
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+
+	if len(lst) == 0 {
+		var empty T
+		return empty, domain.ErrNotFound
+	}
+	return lst[0], nil
+}
+
+// GetById returns the row with the psgc_code, or domain.ErrNotFound
+func (t *Table[T]) GetById(ctx context.Context, psgcCode string) (T, error) {
+	return t.Get(ctx, Eq("psgc_code", psgcCode))
+}
+
+// Paginate returns one page of the rows matching the filters and the
+// keyword and filter expression in params. The totals in the metadata count
+// the same rows.
+func (t *Table[T]) Paginate(
+	ctx context.Context,
+	params domain.PaginationParams,
+	filters ...Filter,
+) ([]T, domain.MetaData, error) {
+	q := t.query(filters).
+		Keyword(params.Keyword, "psgc_code", "name").
+		Expression(params.Filter)
+
+	// Sort by the requested field, psgc_code by default.
+	query, queryParams, cursor, err := q.Page(params, t.sortFields)
+	if err != nil {
+		return nil, domain.MetaData{}, err
+	}
+
+	lst, err := t.fetch(ctx, query, queryParams...)
+	if err != nil {
+		return nil, domain.MetaData{}, err
+	}
+
+	// The count uses the same filters, so the totals match the keyword.
+	countQuery, countParams := q.Count()
+	totalItems := 0
+	if err := t.conn.QueryRowContext(ctx, countQuery, countParams...).Scan(&totalItems); err != nil {
+		return nil, domain.MetaData{}, err
+	}
+
+	lst, links := KeysetPage(lst, params, cursor, t.sortValue)
+
+	if len(lst) == 0 {
+		lst = []T{}
+	}
+
+	return lst, NewMetaData(params, cursor, totalItems, len(lst), links), nil
+}
+
+// Each calls fn for every row matching the filters and the keyword and
+// filter expression in params, in the sort order of params. Rows are read
+// one at a time, so the result set is never held in memory.
+func (t *Table[T]) Each(
+	ctx context.Context,
+	params domain.PaginationParams,
+	fn func(item T) error,
+	filters ...Filter,
+) error {
+	q := t.query(filters).
+		Keyword(params.Keyword, "psgc_code", "name").
+		Expression(params.Filter)
+	query, args := q.Select()
+
+	return t.each(ctx, query+orderBy(params, t.sortFields), args, fn)
+}
+
+// Insert writes an item, updating the row with the same psgc_code. It is an
+// upsert rather than INSERT OR REPLACE, which deletes the old row first and
+// would cascade to its children.
+func (t *Table[T]) Insert(ctx context.Context, item T) error {
+	updates := make([]string, 0, len(t.columns))
+	for _, column := range t.columns[1:] {
+		updates = append(updates, column.Name+" = excluded."+column.Name)
+	}
+
+	query := fmt.Sprintf(
+		"INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (psgc_code) DO UPDATE SET %s;",
+		t.name,
+		t.columnNames(),
+		strings.TrimSuffix(strings.Repeat("?, ", len(t.columns)), ", "),
+		strings.Join(updates, ", "),
+	)
+
+	ctx, span := spanWithQuery(ctx, t.tracer, query)
+	defer span.End()
+
+	args := make([]interface{}, len(t.columns))
+	for i, column := range t.columns {
+		args[i] = column.Field(&item)
+	}
+
+	if _, err := t.conn.ExecContext(ctx, query, args...); err != nil {
+		span.SetStatus(codes.Error, "failed inserting "+t.name)
+		span.RecordError(err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package rpc
+
+import (
+	"github.com/Brix101/psgc-tool/internal/domain"
+	psgcv1 "github.com/Brix101/psgc-tool/proto/psgc/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// levels maps the levels of the masterlist to the protobuf enum
+var levels = map[string]psgcv1.Level{
+	domain.LevelRegion:       psgcv1.Level_LEVEL_REGION,
+	domain.LevelProvince:     psgcv1.Level_LEVEL_PROVINCE,
+	domain.LevelCity:         psgcv1.Level_LEVEL_CITY,
+	domain.LevelMunicipality: psgcv1.Level_LEVEL_MUNICIPALITY,
+	domain.LevelSGU:          psgcv1.Level_LEVEL_SGU,
+	domain.LevelBarangay:     psgcv1.Level_LEVEL_BARANGAY,
+}
+
+// fromLevel returns the masterlist level of a request, empty when it's
+// unspecified
+func fromLevel(level psgcv1.Level) (string, error) {
+	if level == psgcv1.Level_LEVEL_UNSPECIFIED {
+		return "", nil
+	}
+
+	for name, l := range levels {
+		if l == level {
+			return name, nil
+		}
+	}
+
+	return "", status.Errorf(codes.InvalidArgument, "unknown level %d", level)
+}
+
+func toUnit(unit domain.GeoUnit) *psgcv1.Unit {
+	return &psgcv1.Unit{
+		PsgcCode:        unit.PsgcCode,
+		Name:            unit.Name,
+		Level:           levels[unit.Level],
+		ParentCode:      unit.ParentCode,
+		Population_2015: int64(unit.Population2015),
+		Population_2020: int64(unit.Population2020),
+	}
+}
+
+func toUnits(lst []domain.GeoUnit) []*psgcv1.Unit {
+	units := make([]*psgcv1.Unit, len(lst))
+	for i, unit := range lst {
+		units[i] = toUnit(unit)
+	}
+
+	return units
+}
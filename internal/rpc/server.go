@@ -0,0 +1,267 @@
+// Package rpc serves the unified geographic hierarchy as the psgc.v1 gRPC
+// service, for backend services that would otherwise wrap the REST API
+package rpc
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	psgcv1 "github.com/Brix101/psgc-tool/proto/psgc/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 1000
+	// MaxBatchSize is the most codes a BatchGet may ask for
+	MaxBatchSize = 1000
+)
+
+type service struct {
+	psgcv1.UnimplementedPsgcServiceServer
+
+	logger      *zap.Logger
+	geoUnitRepo domain.HierarchyRepository
+}
+
+// NewServer returns a gRPC server with the psgc.v1 service and server
+// reflection, for tools like grpcurl
+func NewServer(logger *zap.Logger, geoUnitRepo domain.HierarchyRepository) *grpc.Server {
+	server := grpc.NewServer()
+	psgcv1.RegisterPsgcServiceServer(server, &service{
+		logger:      logger,
+		geoUnitRepo: geoUnitRepo,
+	})
+	reflection.Register(server)
+
+	return server
+}
+
+func (s *service) GetUnit(
+	ctx context.Context,
+	req *psgcv1.GetUnitRequest,
+) (*psgcv1.Unit, error) {
+	if err := validateCode("psgc_code", req.GetPsgcCode()); err != nil {
+		return nil, err
+	}
+
+	unit, err := s.geoUnitRepo.GetById(ctx, req.GetPsgcCode())
+	if err != nil {
+		return nil, s.repositoryError(err, "failed to fetch unit")
+	}
+
+	return toUnit(unit), nil
+}
+
+func (s *service) ListUnits(
+	ctx context.Context,
+	req *psgcv1.ListUnitsRequest,
+) (*psgcv1.ListUnitsResponse, error) {
+	if req.GetParentCode() != "" && req.GetAncestorCode() != "" {
+		return nil, status.Error(codes.InvalidArgument, "parent_code and ancestor_code can't be combined")
+	}
+	for field, code := range map[string]string{
+		"parent_code":   req.GetParentCode(),
+		"ancestor_code": req.GetAncestorCode(),
+	} {
+		if code == "" {
+			continue
+		}
+		if err := validateCode(field, code); err != nil {
+			return nil, err
+		}
+	}
+
+	level, err := fromLevel(req.GetLevel())
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := paginationParams(req.GetOrderBy(), req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	params.Keyword = req.GetKeyword()
+
+	var page domain.PaginatedGeoUnit
+	if req.GetAncestorCode() != "" {
+		page, err = s.geoUnitRepo.Descendants(ctx, req.GetAncestorCode(), level, params)
+	} else {
+		params.Parent = req.GetParentCode()
+		page, err = s.geoUnitRepo.GetAll(ctx, level, params)
+	}
+	if err != nil {
+		return nil, s.repositoryError(err, "failed to list units")
+	}
+
+	return &psgcv1.ListUnitsResponse{
+		Units:         toUnits(page.Data),
+		NextPageToken: page.MetaData.NextCursor,
+		TotalSize:     int32(page.MetaData.TotalItems),
+	}, nil
+}
+
+func (s *service) BatchGet(
+	ctx context.Context,
+	req *psgcv1.BatchGetRequest,
+) (*psgcv1.BatchGetResponse, error) {
+	if len(req.GetPsgcCodes()) > MaxBatchSize {
+		return nil, status.Errorf(codes.InvalidArgument, "psgc_codes should have at most %d codes", MaxBatchSize)
+	}
+	for _, code := range req.GetPsgcCodes() {
+		if err := validateCode("psgc_codes", code); err != nil {
+			return nil, err
+		}
+	}
+
+	lst, err := s.geoUnitRepo.GetByIds(ctx, req.GetPsgcCodes())
+	if err != nil {
+		return nil, s.repositoryError(err, "failed to fetch units")
+	}
+
+	byCode := make(map[string]domain.GeoUnit, len(lst))
+	for _, unit := range lst {
+		byCode[unit.PsgcCode] = unit
+	}
+
+	res := &psgcv1.BatchGetResponse{Units: []*psgcv1.Unit{}, NotFound: []string{}}
+	for _, code := range req.GetPsgcCodes() {
+		if unit, ok := byCode[code]; ok {
+			res.Units = append(res.Units, toUnit(unit))
+		} else {
+			res.NotFound = append(res.NotFound, code)
+		}
+	}
+
+	return res, nil
+}
+
+func (s *service) Ancestors(
+	ctx context.Context,
+	req *psgcv1.AncestorsRequest,
+) (*psgcv1.AncestorsResponse, error) {
+	if err := validateCode("psgc_code", req.GetPsgcCode()); err != nil {
+		return nil, err
+	}
+
+	lst, err := s.geoUnitRepo.Ancestors(ctx, req.GetPsgcCode())
+	if err != nil {
+		return nil, s.repositoryError(err, "failed to fetch ancestors")
+	}
+
+	return &psgcv1.AncestorsResponse{Units: toUnits(lst)}, nil
+}
+
+func (s *service) Search(
+	ctx context.Context,
+	req *psgcv1.SearchRequest,
+) (*psgcv1.SearchResponse, error) {
+	if strings.TrimSpace(req.GetQuery()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+
+	level, err := fromLevel(req.GetLevel())
+	if err != nil {
+		return nil, err
+	}
+
+	params, err := paginationParams("population_2020 desc", req.GetPageSize(), req.GetPageToken())
+	if err != nil {
+		return nil, err
+	}
+	params.Keyword = strings.TrimSpace(req.GetQuery())
+
+	page, err := s.geoUnitRepo.GetAll(ctx, level, params)
+	if err != nil {
+		return nil, s.repositoryError(err, "failed to search units")
+	}
+
+	return &psgcv1.SearchResponse{
+		Units:         toUnits(page.Data),
+		NextPageToken: page.MetaData.NextCursor,
+		TotalSize:     int32(page.MetaData.TotalItems),
+	}, nil
+}
+
+// paginationParams reads the paging fields of a request. A page token is a
+// cursor of the REST API, it keeps the order of the list it was taken from.
+func paginationParams(orderBy string, pageSize int32, pageToken string) (domain.PaginationParams, error) {
+	params := domain.PaginationParams{
+		Page:    1,
+		PerPage: int(pageSize),
+		Sort:    "psgc_code",
+		Order:   domain.OrderAsc,
+		Cursor:  pageToken,
+	}
+
+	switch {
+	case pageSize == 0:
+		params.PerPage = DefaultPageSize
+	case pageSize < 0 || pageSize > MaxPageSize:
+		return params, status.Errorf(codes.InvalidArgument, "page_size should be between 1 and %d", MaxPageSize)
+	}
+
+	if orderBy != "" {
+		fields := strings.Fields(orderBy)
+		if len(fields) > 2 || (len(fields) == 2 && fields[1] != domain.OrderDesc && fields[1] != domain.OrderAsc) {
+			return params, status.Error(codes.InvalidArgument, `order_by should be a field optionally followed by "desc"`)
+		}
+
+		params.Sort = fields[0]
+		if len(fields) == 2 {
+			params.Order = fields[1]
+		}
+	}
+
+	if !slices.Contains(domain.GeoUnitSortFields, params.Sort) {
+		return params, status.Errorf(codes.InvalidArgument,
+			"order_by should be one of %s", strings.Join(domain.GeoUnitSortFields, ", "))
+	}
+
+	if pageToken != "" {
+		cursor, err := domain.DecodeCursor(pageToken)
+		if err != nil {
+			return params, status.Error(codes.InvalidArgument, "invalid page_token")
+		}
+
+		if cursor.Sort != params.Sort || cursor.Order != params.Order {
+			return params, status.Error(codes.InvalidArgument, "page_token does not match order_by")
+		}
+	}
+
+	return params, nil
+}
+
+// validateCode rejects anything but a 10 digit PSGC code
+func validateCode(field, code string) error {
+	if len(code) != 10 || strings.Trim(code, "0123456789") != "" {
+		return status.Errorf(codes.InvalidArgument, "%s should be a 10 digit PSGC code, got %q", field, code)
+	}
+
+	return nil
+}
+
+// repositoryError maps a failed repository call to a status. Failures of
+// the server are logged and sent without their details, like the REST API.
+func (s *service) repositoryError(err error, msg string) error {
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, "invalid page_token")
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	s.logger.Error(msg, zap.Error(err))
+	return status.Error(codes.Internal, msg)
+}
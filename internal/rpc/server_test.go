@@ -0,0 +1,185 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	psgcv1 "github.com/Brix101/psgc-tool/proto/psgc/v1"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPaginationParams(t *testing.T) {
+	byName := domain.Cursor{Sort: "name", Order: domain.OrderAsc, Value: "Adams", PsgcCode: "0102801000"}.Encode()
+	byPopulation := domain.Cursor{Sort: "population_2020", Order: domain.OrderDesc, Value: 1000, PsgcCode: "0102801000"}.Encode()
+
+	tests := []struct {
+		name      string
+		orderBy   string
+		pageSize  int32
+		pageToken string
+		// want is the sort, order and page size, empty for an error
+		want string
+	}{
+		{"defaults", "", 0, "", "psgc_code asc 100"},
+		{"field", "name", 10, "", "name asc 10"},
+		{"descending", "population_2020 desc", 1000, "", "population_2020 desc 1000"},
+		{"ascending", " name  asc ", 1, "", "name asc 1"},
+		{"unknown field", "population", 10, "", ""},
+		{"unknown order", "name down", 10, "", ""},
+		{"upper case order", "name DESC", 10, "", ""},
+		{"too many words", "name asc desc", 10, "", ""},
+		{"negative page size", "", -1, "", ""},
+		{"page size over the limit", "", MaxPageSize + 1, "", ""},
+		{"page token of the order", "name", 10, byName, "name asc 10"},
+		{"page token of the descending order", "population_2020 desc", 10, byPopulation, "population_2020 desc 10"},
+		{"page token of another field", "psgc_code", 10, byName, ""},
+		{"page token of another order", "population_2020", 10, byPopulation, ""},
+		{"invalid page token", "", 10, "bogus", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := paginationParams(tt.orderBy, tt.pageSize, tt.pageToken)
+
+			if tt.want == "" {
+				if status.Code(err) != codes.InvalidArgument {
+					t.Errorf("paginationParams() error = %v, want InvalidArgument", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("paginationParams: %v", err)
+			}
+
+			got := fmt.Sprintf("%s %s %d", params.Sort, params.Order, params.PerPage)
+			if got != tt.want || params.Page != 1 || params.Cursor != tt.pageToken {
+				t.Errorf("paginationParams() = %s page %d cursor %q, want %s page 1 cursor %q", got, params.Page, params.Cursor, tt.want, tt.pageToken)
+			}
+		})
+	}
+}
+
+// fakeHierarchy knows a few units, err fails every call when set
+type fakeHierarchy struct {
+	domain.HierarchyRepository
+	units map[string]domain.GeoUnit
+	err   error
+}
+
+func (f fakeHierarchy) GetById(ctx context.Context, psgcCode string) (domain.GeoUnit, error) {
+	if f.err != nil {
+		return domain.GeoUnit{}, f.err
+	}
+	unit, ok := f.units[psgcCode]
+	if !ok {
+		return unit, domain.ErrNotFound
+	}
+	return unit, nil
+}
+
+// GetByIds returns the units in psgc_code order, like the repositories
+func (f fakeHierarchy) GetByIds(ctx context.Context, psgcCodes []string) ([]domain.GeoUnit, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	sorted := slices.Clone(psgcCodes)
+	slices.Sort(sorted)
+
+	lst := []domain.GeoUnit{}
+	for _, code := range sorted {
+		if unit, ok := f.units[code]; ok {
+			lst = append(lst, unit)
+		}
+	}
+	return lst, nil
+}
+
+func newTestService(repo fakeHierarchy) *service {
+	return &service{logger: zap.NewNop(), geoUnitRepo: repo}
+}
+
+var testUnits = map[string]domain.GeoUnit{
+	"0100000000": {PsgcCode: "0100000000", Name: "Region I", Level: domain.LevelRegion},
+	"0102800000": {PsgcCode: "0102800000", Name: "Ilocos Norte", Level: domain.LevelProvince, ParentCode: "0100000000"},
+	"1300000000": {PsgcCode: "1300000000", Name: "NCR", Level: domain.LevelRegion},
+}
+
+func TestBatchGet(t *testing.T) {
+	s := newTestService(fakeHierarchy{units: testUnits})
+
+	res, err := s.BatchGet(context.Background(), &psgcv1.BatchGetRequest{
+		PsgcCodes: []string{"1300000000", "9900000000", "0100000000", "0000000000", "0102800000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := []string{}
+	for _, unit := range res.GetUnits() {
+		got = append(got, unit.GetPsgcCode())
+	}
+	if want := []string{"1300000000", "0100000000", "0102800000"}; !slices.Equal(got, want) {
+		t.Errorf("units = %v, want %v in the order of the request", got, want)
+	}
+	if want := []string{"9900000000", "0000000000"}; !slices.Equal(res.GetNotFound(), want) {
+		t.Errorf("not_found = %v, want %v in the order of the request", res.GetNotFound(), want)
+	}
+	if unit := res.GetUnits()[2]; unit.GetLevel() != psgcv1.Level_LEVEL_PROVINCE || unit.GetParentCode() != "0100000000" {
+		t.Errorf("unit = %v, want the province of Region I", unit)
+	}
+
+	empty, err := s.BatchGet(context.Background(), &psgcv1.BatchGetRequest{})
+	if err != nil || empty.GetUnits() == nil || empty.GetNotFound() == nil {
+		t.Errorf("BatchGet(no codes) = %v, %v, want empty lists", empty, err)
+	}
+
+	tooMany := make([]string, MaxBatchSize+1)
+	for i := range tooMany {
+		tooMany[i] = "0100000000"
+	}
+	if _, err := s.BatchGet(context.Background(), &psgcv1.BatchGetRequest{PsgcCodes: tooMany}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("BatchGet(%d codes) error = %v, want InvalidArgument", len(tooMany), err)
+	}
+	if _, err := s.BatchGet(context.Background(), &psgcv1.BatchGetRequest{PsgcCodes: []string{"01"}}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("BatchGet(malformed code) error = %v, want InvalidArgument", err)
+	}
+}
+
+func TestRepositoryError(t *testing.T) {
+	tests := []struct {
+		err  error
+		want codes.Code
+	}{
+		{domain.ErrNotFound, codes.NotFound},
+		{fmt.Errorf("lookup: %w", domain.ErrNotFound), codes.NotFound},
+		{domain.ErrInvalidCursor, codes.InvalidArgument},
+		{context.Canceled, codes.Canceled},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), codes.DeadlineExceeded},
+		{errors.New("no such table: geo_unit"), codes.Internal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			s := newTestService(fakeHierarchy{err: tt.err})
+
+			_, err := s.GetUnit(context.Background(), &psgcv1.GetUnitRequest{PsgcCode: "0100000000"})
+			if status.Code(err) != tt.want {
+				t.Fatalf("GetUnit() error = %v, want %s", err, tt.want)
+			}
+			if tt.want == codes.Internal && status.Convert(err).Message() != "failed to fetch unit" {
+				t.Errorf("internal error %q sent with its details", status.Convert(err).Message())
+			}
+		})
+	}
+
+	s := newTestService(fakeHierarchy{units: testUnits})
+	if unit, err := s.GetUnit(context.Background(), &psgcv1.GetUnitRequest{PsgcCode: "0100000000"}); err != nil || unit.GetName() != "Region I" {
+		t.Errorf("GetUnit() = %v, %v, want Region I", unit, err)
+	}
+}
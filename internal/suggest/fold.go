@@ -0,0 +1,60 @@
+package suggest
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// latin1 folds the accented letters of Latin-1 to ASCII
+var latin1 = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i",
+	'ð': "d",
+	'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u",
+	'ý': "y", 'ÿ': "y",
+	'þ': "th",
+	'ß': "ss",
+}
+
+// Fold normalizes a name or a query for prefix matching: lower case,
+// without diacritics, with every run of anything but letters and digits
+// turned into one space. The masterlist is read as Latin-1, so a byte that
+// isn't valid UTF-8 is taken as a Latin-1 letter, e.g. "Pi\xf1as" is
+// "pinas" like "Piñas".
+func Fold(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	space := true // no leading space
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			r = rune(s[i])
+		}
+		i += size
+
+		r = unicode.ToLower(r)
+		switch {
+		case r < utf8.RuneSelf && (r >= 'a' && r <= 'z' || r >= '0' && r <= '9'):
+			b.WriteRune(r)
+		case latin1[r] != "":
+			b.WriteString(latin1[r])
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			if !space {
+				b.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+	}
+
+	return strings.TrimSuffix(b.String(), " ")
+}
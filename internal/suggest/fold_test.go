@@ -0,0 +1,27 @@
+package suggest
+
+import "testing"
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		s, want string
+	}{
+		{"Las Piñas", "las pinas"},
+		{"Las Pi\xf1as", "las pinas"},
+		{"LAS PI\xd1AS", "las pinas"},
+		{"  City of  Taguig ", "city of taguig"},
+		{"Sto. Niño (Pob.)", "sto nino pob"},
+		{"Dasmariñas-Bayan", "dasmarinas bayan"},
+		{"50th District", "50th district"},
+		{"Æther Þorp Straße", "aether thorp strasse"},
+		{"Ĉu", "ĉu"},
+		{"...", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Fold(tt.s); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.s, got, tt.want)
+		}
+	}
+}
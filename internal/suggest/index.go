@@ -0,0 +1,162 @@
+// Package suggest answers type-ahead queries from a prefix index of every
+// unit name, built in memory at startup
+package suggest
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+const (
+	DefaultLimit = 10
+	// MaxLimit is the most suggestions a query may ask for, it is also how
+	// many units of a level every node of the index keeps
+	MaxLimit = 50
+)
+
+// Index is a trie of the folded unit names. A name is inserted from the
+// start of each of its words, so "taguig" finds "City of Taguig".
+//
+// Units are numbered by rank: the upper levels first, then the most
+// populous. Every node keeps the best MaxLimit units of each level below
+// it, in rank order, so a query is a walk down the trie and a scan of one
+// short list whatever the size of the result.
+type Index struct {
+	units []domain.GeoUnit
+	// levels are the positions in domain.Levels of the units' levels
+	levels []uint8
+	root   node
+}
+
+type node struct {
+	labels   []byte
+	children []*node
+	// ids are the ranks of the best units below the node
+	ids []int32
+}
+
+// Build reads every unit of the hierarchy into a new Index
+func Build(ctx context.Context, geoUnitRepo domain.HierarchyRepository) (*Index, error) {
+	units := []domain.GeoUnit{}
+	params := domain.PaginationParams{Sort: "psgc_code", Order: domain.OrderAsc}
+	err := geoUnitRepo.Each(ctx, "", params, func(item domain.GeoUnit) error {
+		units = append(units, item)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return NewIndex(units), nil
+}
+
+// NewIndex indexes units
+func NewIndex(units []domain.GeoUnit) *Index {
+	levels := make(map[string]uint8, len(domain.Levels))
+	for i, level := range domain.Levels {
+		levels[level] = uint8(i)
+	}
+
+	slices.SortStableFunc(units, func(a, b domain.GeoUnit) int {
+		if levels[a.Level] != levels[b.Level] {
+			return int(levels[a.Level]) - int(levels[b.Level])
+		}
+		if a.Population2020 != b.Population2020 {
+			return b.Population2020 - a.Population2020
+		}
+		return strings.Compare(a.PsgcCode, b.PsgcCode)
+	})
+
+	idx := &Index{units: units, levels: make([]uint8, len(units))}
+	for id, unit := range units {
+		idx.levels[id] = levels[unit.Level]
+
+		key := Fold(unit.Name)
+		for start := 0; start < len(key); {
+			idx.insert(key[start:], int32(id))
+
+			next := strings.IndexByte(key[start:], ' ')
+			if next < 0 {
+				break
+			}
+			start += next + 1
+		}
+	}
+
+	return idx
+}
+
+// insert adds id to the nodes of key. Units are inserted in rank order, so
+// appending keeps every list ranked and the units of a level at its tail.
+func (idx *Index) insert(key string, id int32) {
+	n := &idx.root
+	for i := 0; i < len(key); i++ {
+		n = n.child(key[i])
+
+		// A unit is inserted once for every word, "San Jose de San Juan"
+		// reaches the nodes of "san" twice
+		if len(n.ids) > 0 && n.ids[len(n.ids)-1] == id {
+			continue
+		}
+
+		sameLevel := 0
+		for j := len(n.ids) - 1; j >= 0 && idx.levels[n.ids[j]] == idx.levels[id]; j-- {
+			sameLevel++
+		}
+		if sameLevel < MaxLimit {
+			n.ids = append(n.ids, id)
+		}
+	}
+}
+
+func (n *node) child(label byte) *node {
+	for i, l := range n.labels {
+		if l == label {
+			return n.children[i]
+		}
+	}
+
+	child := &node{}
+	n.labels = append(n.labels, label)
+	n.children = append(n.children, child)
+	return child
+}
+
+// Suggest returns up to limit units whose name has a word starting with q,
+// best first. levels limits them to some levels, all levels when empty.
+func (idx *Index) Suggest(q string, levels []string, limit int) []domain.GeoUnit {
+	suggestions := []domain.GeoUnit{}
+
+	key := Fold(q)
+	if key == "" {
+		return suggestions
+	}
+
+	n := &idx.root
+	for i := 0; i < len(key); i++ {
+		j := slices.Index(n.labels, key[i])
+		if j < 0 {
+			return suggestions
+		}
+		n = n.children[j]
+	}
+
+	var wanted [8]bool
+	for i, level := range domain.Levels {
+		wanted[i] = len(levels) == 0 || slices.Contains(levels, level)
+	}
+
+	for _, id := range n.ids {
+		if len(suggestions) == limit {
+			break
+		}
+		if wanted[idx.levels[id]] {
+			suggestions = append(suggestions, idx.units[id])
+		}
+	}
+
+	return suggestions
+}
@@ -0,0 +1,149 @@
+package suggest
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+func codes(units []domain.GeoUnit) []string {
+	lst := []string{}
+	for _, unit := range units {
+		lst = append(lst, unit.PsgcCode)
+	}
+	return lst
+}
+
+func TestSuggest(t *testing.T) {
+	units := []domain.GeoUnit{
+		{PsgcCode: "1380100000", Name: "City of Las Pi\xf1as", Level: domain.LevelCity, Population2020: 606293},
+		{PsgcCode: "1381500000", Name: "City of Taguig", Level: domain.LevelCity, Population2020: 886722},
+		{PsgcCode: "1300000000", Name: "National Capital Region (NCR)", Level: domain.LevelRegion, Population2020: 13484462},
+		{PsgcCode: "0402100000", Name: "Cavite", Level: domain.LevelProvince, Population2020: 4344829},
+		{PsgcCode: "0402106000", Name: "City of Dasmari\xf1as", Level: domain.LevelCity, Population2020: 703141},
+		{PsgcCode: "0402106001", Name: "Burol", Level: domain.LevelBarangay, Population2020: 4000},
+		{PsgcCode: "0402106002", Name: "San Agustin I", Level: domain.LevelBarangay, Population2020: 9000},
+		{PsgcCode: "0402106003", Name: "San Agustin II", Level: domain.LevelBarangay, Population2020: 9000},
+		{PsgcCode: "0314010000", Name: "San Jose del Monte", Level: domain.LevelCity, Population2020: 651813},
+		{PsgcCode: "0410900000", Name: "San Jose de San Juan", Level: domain.LevelMunicipality, Population2020: 1000},
+		{PsgcCode: "1999901000", Name: "Datu Piang", Level: domain.LevelSGU, Population2020: 1500},
+	}
+
+	idx := NewIndex(slices.Clone(units))
+
+	tests := []struct {
+		name   string
+		q      string
+		levels []string
+		limit  int
+		want   []string
+	}{
+		{
+			name:  "folds the query and the names",
+			q:     "las pinas",
+			limit: 10,
+			want:  []string{"1380100000"},
+		},
+		{
+			name:  "accents and case in the query",
+			q:     "LAS PIÑAS",
+			limit: 10,
+			want:  []string{"1380100000"},
+		},
+		{
+			name:  "any word of the name",
+			q:     "taguig",
+			limit: 10,
+			want:  []string{"1381500000"},
+		},
+		{
+			name:  "not inside a word",
+			q:     "aguig",
+			limit: 10,
+			want:  []string{},
+		},
+		{
+			name:  "upper levels first, then the most populous",
+			q:     "c",
+			limit: 10,
+			want:  []string{"1300000000", "0402100000", "1381500000", "0402106000", "1380100000"},
+		},
+		{
+			name:  "ties ranked by psgc_code",
+			q:     "san agustin",
+			limit: 10,
+			want:  []string{"0402106002", "0402106003"},
+		},
+		{
+			name:  "a name with the word twice comes once",
+			q:     "san",
+			limit: 10,
+			want:  []string{"0314010000", "0410900000", "0402106002", "0402106003"},
+		},
+		{
+			name:   "levels",
+			q:      "san",
+			levels: []string{domain.LevelMunicipality, domain.LevelBarangay},
+			limit:  10,
+			want:   []string{"0410900000", "0402106002", "0402106003"},
+		},
+		{
+			name:  "limit",
+			q:     "city",
+			limit: 2,
+			want:  []string{"1381500000", "0402106000"},
+		},
+		{
+			name:  "punctuation only",
+			q:     "(.)",
+			limit: 10,
+			want:  []string{},
+		},
+		{
+			name:  "no match",
+			q:     "zamboanga",
+			limit: 10,
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := codes(idx.Suggest(tt.q, tt.levels, tt.limit)); !slices.Equal(got, tt.want) {
+				t.Errorf("Suggest(%q) = %v, want %v", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSuggestKeepsEveryLevel checks that a node full of units of one level
+// still has the units of the other levels
+func TestSuggestKeepsEveryLevel(t *testing.T) {
+	units := []domain.GeoUnit{}
+	for i := 0; i < 2*MaxLimit; i++ {
+		units = append(units, domain.GeoUnit{
+			PsgcCode:       fmt.Sprintf("04021%05d", i+1),
+			Name:           fmt.Sprintf("Poblacion %d", i+1),
+			Level:          domain.LevelBarangay,
+			Population2020: 10000 - i,
+		})
+	}
+	units = append(units, domain.GeoUnit{PsgcCode: "0402100000", Name: "Pozorrubio", Level: domain.LevelMunicipality})
+
+	idx := NewIndex(units)
+
+	got := idx.Suggest("po", nil, MaxLimit)
+	if len(got) != MaxLimit || got[0].PsgcCode != "0402100000" {
+		t.Fatalf("Suggest(po) = %v, want the municipality then barangays, %d units", codes(got), MaxLimit)
+	}
+	if got[1].PsgcCode != "0402100001" || got[MaxLimit-1].PsgcCode != fmt.Sprintf("04021%05d", MaxLimit-1) {
+		t.Errorf("Suggest(po) = %v, want the most populous barangays", codes(got))
+	}
+
+	got = idx.Suggest("poblacion", []string{domain.LevelBarangay}, MaxLimit)
+	if len(got) != MaxLimit {
+		t.Errorf("Suggest(poblacion) = %d units, want %d", len(got), MaxLimit)
+	}
+}
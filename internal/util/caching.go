@@ -0,0 +1,174 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+const (
+	// PinnedCacheControl is sent for URLs pinned to an edition with
+	// ?edition=, their response never changes
+	PinnedCacheControl = "public, max-age=31536000, immutable"
+	// DefaultCacheControl is sent for every other URL, they change when a new
+	// edition is generated
+	DefaultCacheControl = "public, max-age=3600"
+	// LongCacheControl is sent by LongLived routes, whose responses are
+	// fine to serve for a day past a new edition
+	LongCacheControl = "public, max-age=86400, stale-while-revalidate=604800"
+)
+
+// Caching is a middleware for HTTP caching of GET requests. The data only
+// changes with a new edition, so every response gets
+//   - a strong ETag derived from the edition and the request
+//   - Last-Modified set to the edition date
+//   - Cache-Control, long-lived for URLs with ?edition= set to the edition
+//
+// and If-None-Match/If-Modified-Since are answered with 304 in place of the
+// handler's 200, only a unit that exists is unchanged. A request pinned to
+// any other edition is a 404. Error responses are sent without the caching
+// headers.
+func Caching(edition time.Time) func(http.Handler) http.Handler {
+	editionName := edition.Format(EditionLayout)
+	lastModified := edition.UTC().Format(http.TimeFormat)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method != http.MethodGet && r.Method != http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("X-Edition", editionName)
+
+			cacheControl := DefaultCacheControl
+			if pinned := r.URL.Query().Get("edition"); pinned != "" {
+				if pinned != editionName {
+					Error(w, r, http.StatusNotFound, domain.CodeNotFound,
+						fmt.Sprintf("edition %s is not available, the current edition is %s.", pinned, editionName))
+					return
+				}
+				cacheControl = PinnedCacheControl
+			}
+
+			etag := requestETag(editionName, r)
+
+			w.Header().Set("ETag", etag)
+			w.Header().Set("Last-Modified", lastModified)
+			w.Header().Set("Cache-Control", cacheControl)
+
+			next.ServeHTTP(&cachingWriter{
+				ResponseWriter: w,
+				notModified:    notModified(r, etag, edition),
+			}, r)
+		})
+	}
+}
+
+// LongLived is a middleware for routes whose responses can be cached longer
+// than DefaultCacheControl, it runs after Caching. Pinned URLs keep
+// PinnedCacheControl.
+func LongLived(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if w.Header().Get("Cache-Control") == DefaultCacheControl {
+			w.Header().Set("Cache-Control", LongCacheControl)
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestETag hashes everything a response depends on, the edition, the
+// path, the query and the headers the API varies on
+func requestETag(editionName string, r *http.Request) string {
+	h := sha256.New()
+	for _, s := range []string{
+		editionName,
+		r.URL.Path,
+		r.URL.Query().Encode(), // sorted by key
+		r.Header.Get("Accept"),
+		r.Header.Get("Accept-Encoding"),
+	} {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// notModified reports whether the client's copy is current. If-None-Match
+// takes precedence over If-Modified-Since, as in RFC 9110.
+func notModified(r *http.Request, etag string, edition time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, tag := range strings.Split(inm, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		t, err := http.ParseTime(ims)
+		return err == nil && !edition.After(t)
+	}
+
+	return false
+}
+
+// cachingWriter drops the caching headers from error responses, an error
+// isn't a representation of the edition. A 200 to a request whose
+// preconditions hold is sent as a 304 without its body.
+type cachingWriter struct {
+	http.ResponseWriter
+	notModified bool
+
+	wroteHeader bool
+	discard     bool
+}
+
+func (w *cachingWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		switch {
+		case status >= http.StatusBadRequest:
+			w.Header().Del("ETag")
+			w.Header().Del("Last-Modified")
+			w.Header().Del("Cache-Control")
+		case status == http.StatusOK && w.notModified:
+			// As http.ServeContent does for a 304
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.Header().Del("Content-Encoding")
+			w.Header().Del("Content-Disposition")
+			status = http.StatusNotModified
+			w.discard = true
+		}
+	}
+	w.wroteHeader = true
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cachingWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.discard {
+		return len(b), nil
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer to flush
+// streamed responses
+func (w *cachingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
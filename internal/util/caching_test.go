@@ -0,0 +1,82 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+func TestCachingPreconditions(t *testing.T) {
+	edition := time.Date(2023, 10, 28, 0, 0, 0, 0, time.UTC)
+
+	// The handler knows a single region
+	handler := Caching(edition)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/regions/0100000000" {
+			Error(w, r, http.StatusNotFound, domain.CodeNotFound, domain.ErrNotFound.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"psgc_code":"0100000000"}`))
+	}))
+
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		for k, vs := range header {
+			r.Header[k] = vs
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	etag := get("/api/regions/0100000000", nil).Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("no ETag on a 200")
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		header http.Header
+		want   int
+	}{
+		{"no precondition", "/api/regions/0100000000", nil, http.StatusOK},
+		{"matching ETag", "/api/regions/0100000000", http.Header{"If-None-Match": {etag}}, http.StatusNotModified},
+		{"weak ETag in a list", "/api/regions/0100000000", http.Header{"If-None-Match": {`"x", W/` + etag}}, http.StatusNotModified},
+		{"other ETag", "/api/regions/0100000000", http.Header{"If-None-Match": {`"x"`}}, http.StatusOK},
+		{"any ETag", "/api/regions/0100000000", http.Header{"If-None-Match": {"*"}}, http.StatusNotModified},
+		{"modified since", "/api/regions/0100000000", http.Header{"If-Modified-Since": {"Fri, 27 Oct 2023 00:00:00 GMT"}}, http.StatusOK},
+		{"not modified since", "/api/regions/0100000000", http.Header{"If-Modified-Since": {"Sat, 28 Oct 2023 00:00:00 GMT"}}, http.StatusNotModified},
+		{"unknown unit, any ETag", "/api/regions/9999999999", http.Header{"If-None-Match": {"*"}}, http.StatusNotFound},
+		{"unknown unit, not modified since", "/api/regions/9999999999", http.Header{"If-Modified-Since": {"Sat, 28 Oct 2023 00:00:00 GMT"}}, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := get(tt.path, tt.header)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d", w.Code, tt.want)
+			}
+
+			switch tt.want {
+			case http.StatusNotModified:
+				if w.Body.Len() > 0 || w.Header().Get("Content-Type") != "" {
+					t.Errorf("304 with a body %q of type %q", w.Body, w.Header().Get("Content-Type"))
+				}
+				if w.Header().Get("ETag") != etag {
+					t.Errorf("304 ETag = %q, want %q", w.Header().Get("ETag"), etag)
+				}
+			case http.StatusNotFound:
+				if w.Header().Get("ETag") != "" || w.Header().Get("Cache-Control") != "" {
+					t.Errorf("error sent with the caching headers %v", w.Header())
+				}
+			}
+		})
+	}
+}
@@ -4,68 +4,263 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 
 	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/Brix101/psgc-tool/internal/filter"
 	"github.com/go-playground/validator/v10"
 )
 
 const (
 	DefaultPage    = 1
 	DefaultPerPage = 100
+	DefaultSort    = "psgc_code"
+	DefaultOrder   = domain.OrderAsc
 )
 
 type PaginateCtx struct{}
 
-func Paginate(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get the "page", "perPage", and "keyword" query parameters from the URL
-		pageParam := r.URL.Query().Get("page")
-		perPageParam := r.URL.Query().Get("per_page")
-		keywordParam := r.URL.Query().Get("keyword")
-
-		// Parse the "page", "perPage", and "keyword" query parameters
-		page, err := strconv.Atoi(pageParam)
-		if err != nil || page <= 0 {
-			page = DefaultPage
-		}
-
-		perPage, err := strconv.Atoi(perPageParam)
-		if err != nil || perPage <= 0 {
-			perPage = DefaultPerPage
-		}
-
-		params := domain.PaginationParams{
-			Page:    page,
-			PerPage: perPage,
-			Keyword: keywordParam,
-		}
-
-		validate := validator.New()
-		if err := validate.Struct(params); err != nil {
-			validationErr, isValidationErr := err.(validator.ValidationErrors)
-			if isValidationErr {
-				fieldName := validationErr[0].Namespace()
-				fieldName = strings.ToLower(fieldName[strings.LastIndex(fieldName, ".")+1:])
-				message := fmt.Sprintf(
-					"%s should be less than %s.",
-					fieldName,
-					validationErr[0].Param(),
-				)
-
-				http.Error(w, message, http.StatusBadRequest)
+// Paginate parses the pagination query parameters. sortFields is the
+// resource's whitelist for the "sort" parameter.
+func Paginate(sortFields ...string) func(http.Handler) http.Handler {
+	return paginate(false, sortFields)
+}
+
+// PaginateAll is Paginate for lists that can also be streamed whole. It
+// accepts per_page=all, which sets PerPage to domain.PerPageAll.
+func PaginateAll(sortFields ...string) func(http.Handler) http.Handler {
+	return paginate(true, sortFields)
+}
+
+func paginate(allowAll bool, sortFields []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Get the "page", "perPage", "keyword", "sort", "order" and "cursor" query parameters from the URL
+			pageParam := r.URL.Query().Get("page")
+			perPageParam := r.URL.Query().Get("per_page")
+			keywordParam := r.URL.Query().Get("keyword")
+			sortParam := r.URL.Query().Get("sort")
+			orderParam := strings.ToLower(r.URL.Query().Get("order"))
+			cursorParam := r.URL.Query().Get("cursor")
+			parentParam := r.URL.Query().Get("parent")
+
+			// Parse the "page", "perPage", and "keyword" query parameters
+			page, err := strconv.Atoi(pageParam)
+			if err != nil || page <= 0 {
+				page = DefaultPage
+			}
+
+			perPage, err := strconv.Atoi(perPageParam)
+			if allowAll && perPageParam == "all" {
+				perPage = domain.PerPageAll
+			} else if err != nil || perPage <= 0 {
+				perPage = DefaultPerPage
+			}
+
+			// A cursor keeps the sort order of the list it was taken from
+			if cursorParam != "" {
+				cursor, err := domain.DecodeCursor(cursorParam)
+				if err != nil {
+					ValidationError(w, r, domain.FieldError{
+						Field:      "cursor",
+						Constraint: "cursor",
+						Message:    err.Error() + ".",
+					})
+					return
+				}
+
+				if (sortParam != "" && sortParam != cursor.Sort) ||
+					(orderParam != "" && orderParam != cursor.Order) {
+					ValidationError(w, r, domain.FieldError{
+						Field:      "cursor",
+						Constraint: "sort=" + cursor.Sort + " order=" + cursor.Order,
+						Message:    "cursor does not match sort and order.",
+					})
+					return
+				}
+
+				sortParam, orderParam = cursor.Sort, cursor.Order
+			}
+
+			if sortParam == "" {
+				sortParam = DefaultSort
+			}
+
+			if orderParam == "" {
+				orderParam = DefaultOrder
+			}
+
+			params := domain.PaginationParams{
+				Page:    page,
+				PerPage: perPage,
+				Keyword: keywordParam,
+				Sort:    sortParam,
+				Order:   orderParam,
+				Parent:  parentParam,
+				Cursor:  cursorParam,
+			}
+
+			// Every invalid parameter is reported, not only the first one
+			details := []domain.FieldError{}
+			validate := validator.New()
+			for _, err := range []error{
+				validate.Struct(params),
+				validate.Var(params.Sort, "oneof="+strings.Join(sortFields, " ")),
+			} {
+				if err == nil {
+					continue
+				}
+
+				validationErrs, isValidationErr := err.(validator.ValidationErrors)
+				if !isValidationErr {
+					Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter, err.Error())
+					return
+				}
+
+				for _, fieldErr := range validationErrs {
+					details = append(details, fieldError(fieldErr))
+				}
+			}
+
+			if len(details) > 0 {
+				ValidationError(w, r, details...)
 				return
 			}
 
-			http.Error(w, err.Error(), http.StatusBadRequest)
-			return
-		}
+			// Create a context with pagination information and pass it down the chain
+			ctx := context.WithValue(r.Context(), PaginateCtx{}, params)
+
+			// Serve the request with the modified context
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Filter parses the attribute filters a list supports, e.g. ?city_class=HUC,
+// into the PaginationParams of Paginate, so it runs after it. A value that
+// isn't one of the attribute's values is a 400 listing them.
+func Filter(attributes ...domain.Attribute) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			params, ok := r.Context().Value(PaginateCtx{}).(domain.PaginationParams)
+			if !ok {
+				Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter, "Pagination information not found")
+				return
+			}
+
+			details := []domain.FieldError{}
+			for _, attribute := range attributes {
+				value := r.URL.Query().Get(attribute.Name)
+				if value == "" {
+					continue
+				}
+
+				if !slices.Contains(attribute.Values, value) {
+					details = append(details, domain.FieldError{
+						Field:      attribute.Name,
+						Constraint: "oneof=" + strings.Join(attribute.Values, " "),
+						Message:    fmt.Sprintf("%s should be one of %s.", attribute.Name, strings.Join(attribute.Values, ", ")),
+					})
+					continue
+				}
+
+				params.SetAttribute(attribute.Name, value)
+			}
+
+			if len(details) > 0 {
+				ValidationError(w, r, details...)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), PaginateCtx{}, params)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// FilterExpr parses the filter expression of a list, ?filter=, over the
+// list's fields into the PaginationParams of Paginate, so it runs after it.
+// An invalid expression is a 400 with the position of the error.
+func FilterExpr(fields filter.Fields) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			params, ok := r.Context().Value(PaginateCtx{}).(domain.PaginationParams)
+			if !ok {
+				Error(w, r, http.StatusBadRequest, domain.CodeInvalidParameter, "Pagination information not found")
+				return
+			}
+
+			if r.URL.Query().Has("filter") {
+				expr, err := filter.Parse(r.URL.Query().Get("filter"), fields)
+				if err != nil {
+					fieldErr := domain.FieldError{Field: "filter", Constraint: "filter", Message: err.Error() + "."}
+					if parseErr, ok := err.(*filter.Error); ok {
+						fieldErr.Position = parseErr.Pos
+					}
+					ValidationError(w, r, fieldErr)
+					return
+				}
+
+				params.Filter = expr
+			}
+
+			ctx := context.WithValue(r.Context(), PaginateCtx{}, params)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// fieldError describes a failed constraint with the query parameter's name
+func fieldError(fieldErr validator.FieldError) domain.FieldError {
+	fieldName := fieldNames[fieldErr.StructField()]
+	if fieldName == "" {
+		// validate.Var has no field, it is only used for the sort whitelist
+		fieldName = "sort"
+	}
+
+	constraint := fieldErr.Tag()
+	if fieldErr.Param() != "" {
+		constraint += "=" + fieldErr.Param()
+	}
+
+	return domain.FieldError{
+		Field:      fieldName,
+		Constraint: constraint,
+		Message:    validationMessage(fieldName, fieldErr),
+	}
+}
+
+// fieldNames maps the PaginationParams fields to their query parameters
+var fieldNames = map[string]string{
+	"Page":    "page",
+	"PerPage": "per_page",
+	"Keyword": "keyword",
+	"Sort":    "sort",
+	"Order":   "order",
+	"Parent":  "parent",
+	"Cursor":  "cursor",
+}
 
-		// Create a context with pagination information and pass it down the chain
-		ctx := context.WithValue(r.Context(), PaginateCtx{}, params)
+func validationMessage(fieldName string, fieldErr validator.FieldError) string {
 
-		// Serve the request with the modified context
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+	switch fieldErr.Tag() {
+	case "numeric":
+		return fmt.Sprintf("%s should be numeric.", fieldName)
+	case "len":
+		return fmt.Sprintf("%s should be %s characters long.", fieldName, fieldErr.Param())
+	case "oneof":
+		return fmt.Sprintf(
+			"%s should be one of %s.",
+			fieldName,
+			strings.Join(strings.Fields(fieldErr.Param()), ", "),
+		)
+	default:
+		return fmt.Sprintf(
+			"%s should be less than %s.",
+			fieldName,
+			fieldErr.Param(),
+		)
+	}
 }
@@ -0,0 +1,123 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+)
+
+// serve runs a request through middleware and returns the response and
+// the PaginationParams the handler got, if it was reached
+func serve(
+	t *testing.T,
+	middleware func(http.Handler) http.Handler,
+	query url.Values,
+) (*httptest.ResponseRecorder, *domain.PaginationParams) {
+	t.Helper()
+
+	var got *domain.PaginationParams
+	handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		params := r.Context().Value(PaginateCtx{}).(domain.PaginationParams)
+		got = &params
+	}))
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/provinces?"+query.Encode(), nil))
+
+	return w, got
+}
+
+// problemFields returns the fields of the details of a problem response
+func problemFields(t *testing.T, w *httptest.ResponseRecorder) []string {
+	t.Helper()
+
+	var problem domain.Problem
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("decoding problem: %v", err)
+	}
+
+	fields := []string{}
+	for _, detail := range problem.Details {
+		fields = append(fields, detail.Field)
+	}
+	return fields
+}
+
+func TestPaginateCursor(t *testing.T) {
+	cursor := domain.Cursor{Sort: "name", Order: domain.OrderDesc, Value: "Abra", PsgcCode: "1400100000"}.Encode()
+	sortFields := []string{"psgc_code", "name", "population_2020"}
+
+	tests := []struct {
+		name      string
+		query     url.Values
+		wantSort  string
+		wantOrder string
+		wantField string // of the 400, empty when the request passes
+	}{
+		{
+			name:      "takes the cursor's sort",
+			query:     url.Values{"cursor": {cursor}},
+			wantSort:  "name",
+			wantOrder: domain.OrderDesc,
+		},
+		{
+			name:      "same sort and order",
+			query:     url.Values{"cursor": {cursor}, "sort": {"name"}, "order": {"DESC"}},
+			wantSort:  "name",
+			wantOrder: domain.OrderDesc,
+		},
+		{
+			name:      "other sort",
+			query:     url.Values{"cursor": {cursor}, "sort": {"population_2020"}},
+			wantField: "cursor",
+		},
+		{
+			name:      "other order",
+			query:     url.Values{"cursor": {cursor}, "order": {"asc"}},
+			wantField: "cursor",
+		},
+		{
+			name:      "invalid cursor",
+			query:     url.Values{"cursor": {"bogus"}},
+			wantField: "cursor",
+		},
+		{
+			name:      "no cursor",
+			query:     url.Values{"sort": {"population_2020"}},
+			wantSort:  "population_2020",
+			wantOrder: domain.OrderAsc,
+		},
+		{
+			name:      "sort outside the whitelist",
+			query:     url.Values{"sort": {"reg_code"}},
+			wantField: "sort",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, params := serve(t, Paginate(sortFields...), tt.query)
+
+			if tt.wantField != "" {
+				if w.Code != http.StatusBadRequest {
+					t.Fatalf("status = %d, want 400", w.Code)
+				}
+				if fields := problemFields(t, w); len(fields) != 1 || fields[0] != tt.wantField {
+					t.Errorf("problem fields = %v, want [%s]", fields, tt.wantField)
+				}
+				return
+			}
+
+			if params == nil {
+				t.Fatalf("status = %d, the handler wasn't reached: %s", w.Code, w.Body)
+			}
+			if params.Sort != tt.wantSort || params.Order != tt.wantOrder {
+				t.Errorf("sort, order = %s %s, want %s %s", params.Sort, params.Order, tt.wantSort, tt.wantOrder)
+			}
+		})
+	}
+}
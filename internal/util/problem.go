@@ -0,0 +1,56 @@
+package util
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/Brix101/psgc-tool/internal/domain"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// ProblemContentType is the media type of an error response, RFC 7807
+const ProblemContentType = "application/problem+json"
+
+// Error writes an error response with a single message
+func Error(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	WriteProblem(w, r, domain.Problem{Status: status, Code: code, Message: message})
+}
+
+// ValidationError writes a 400 listing every invalid parameter
+func ValidationError(w http.ResponseWriter, r *http.Request, details ...domain.FieldError) {
+	messages := []string{}
+	for _, detail := range details {
+		messages = append(messages, detail.Message)
+	}
+
+	WriteProblem(w, r, domain.Problem{
+		Status:  http.StatusBadRequest,
+		Code:    domain.CodeInvalidParameter,
+		Message: strings.Join(messages, " "),
+		Details: details,
+	})
+}
+
+// WriteProblem fills in the members of p that come from the request and
+// writes it
+func WriteProblem(w http.ResponseWriter, r *http.Request, p domain.Problem) {
+	if p.Type == "" {
+		p.Type = "about:blank"
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	if p.Detail == "" {
+		p.Detail = p.Message
+	}
+	p.Instance = r.URL.Path
+	p.RequestID = middleware.GetReqID(r.Context())
+
+	w.Header().Del("Content-Disposition")
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+
+	_ = json.NewEncoder(w).Encode(p)
+}
@@ -0,0 +1,43 @@
+package util
+
+import "net/http"
+
+// StartedWriter records whether a response has started. Until the status
+// line is sent a handler that fails can still answer with a problem, after
+// it the client already has a 200.
+type StartedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func NewStartedWriter(w http.ResponseWriter) *StartedWriter {
+	return &StartedWriter{ResponseWriter: w}
+}
+
+// Started reports whether the status line or any of the body reached the
+// underlying writer
+func (w *StartedWriter) Started() bool {
+	return w.started
+}
+
+func (w *StartedWriter) WriteHeader(status int) {
+	w.started = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *StartedWriter) Write(b []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(b)
+}
+
+// FlushError flushes through http.ResponseController, a flush sends the
+// status line too
+func (w *StartedWriter) FlushError() error {
+	w.started = true
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *StartedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/fs"
 	"os"
+	"time"
 
 	psgctool "github.com/Brix101/psgc-tool"
 	_ "github.com/mattn/go-sqlite3"
@@ -27,20 +28,51 @@ func NewLogger(service string) *zap.Logger {
 	return logger
 }
 
-func NewSQLitePool(ctx context.Context) (*sql.DB, error) {
+// latestDB returns the file name of the newest edition, e.g.
+// 2023-10-28-data.db. Files are named after their edition date so the last
+// one in name order is the newest.
+func latestDB() (string, error) {
 	entries, err := fs.ReadDir(psgctool.EmbedDB, "db")
 	if err != nil {
-		return nil, err
+		return "", err
 	}
 
 	if len(entries) == 0 {
-		return nil, fmt.Errorf("no .db files found in embedded data")
+		return "", fmt.Errorf("no .db files found in embedded data")
+	}
+
+	return entries[len(entries)-1].Name(), nil
+}
+
+// Edition returns the date of the data the API serves, taken from the name of
+// the newest .db file
+func Edition() (time.Time, error) {
+	name, err := latestDB()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if len(name) < len(EditionLayout) {
+		return time.Time{}, fmt.Errorf("%s is not named after its edition date", name)
+	}
+
+	return time.Parse(EditionLayout, name[:len(EditionLayout)])
+}
+
+// EditionLayout is the format of an edition date
+const EditionLayout = "2006-01-02"
+
+func NewSQLitePool(ctx context.Context) (*sql.DB, error) {
+	name, err := latestDB()
+	if err != nil {
+		return nil, err
 	}
 
-	latestEntry := entries[len(entries)-1]
-	dbFile := "db/" + latestEntry.Name()
+	dbFile := "db/" + name
 
-	db, err := sql.Open("sqlite3", dbFile)
+	// Foreign keys are off by default in SQLite and the pragma only applies to
+	// one connection, so it is set in the DSN for every connection in the pool
+	db, err := sql.Open("sqlite3", dbFile+"?_foreign_keys=on")
 	if err != nil {
 		return nil, err
 	}
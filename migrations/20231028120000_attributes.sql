@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE region ADD COLUMN population_2015 INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE region ADD COLUMN population_2020 INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE province ADD COLUMN income_class TEXT NOT NULL DEFAULT '';
+ALTER TABLE province ADD COLUMN population_2015 INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE province ADD COLUMN population_2020 INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE city_muni ADD COLUMN city_class TEXT NOT NULL DEFAULT '';
+ALTER TABLE city_muni ADD COLUMN income_class TEXT NOT NULL DEFAULT '';
+ALTER TABLE city_muni ADD COLUMN status TEXT NOT NULL DEFAULT '';
+ALTER TABLE city_muni ADD COLUMN population_2015 INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE city_muni ADD COLUMN population_2020 INTEGER NOT NULL DEFAULT 0;
+
+ALTER TABLE barangay ADD COLUMN urban_rural TEXT NOT NULL DEFAULT '';
+ALTER TABLE barangay ADD COLUMN status TEXT NOT NULL DEFAULT '';
+ALTER TABLE barangay ADD COLUMN population_2015 INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE barangay ADD COLUMN population_2020 INTEGER NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE barangay DROP COLUMN population_2020;
+ALTER TABLE barangay DROP COLUMN population_2015;
+ALTER TABLE barangay DROP COLUMN status;
+ALTER TABLE barangay DROP COLUMN urban_rural;
+
+ALTER TABLE city_muni DROP COLUMN population_2020;
+ALTER TABLE city_muni DROP COLUMN population_2015;
+ALTER TABLE city_muni DROP COLUMN status;
+ALTER TABLE city_muni DROP COLUMN income_class;
+ALTER TABLE city_muni DROP COLUMN city_class;
+
+ALTER TABLE province DROP COLUMN population_2020;
+ALTER TABLE province DROP COLUMN population_2015;
+ALTER TABLE province DROP COLUMN income_class;
+
+ALTER TABLE region DROP COLUMN population_2020;
+ALTER TABLE region DROP COLUMN population_2015;
+-- +goose StatementEnd
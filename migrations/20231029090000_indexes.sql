@@ -0,0 +1,154 @@
+-- +goose Up
+-- +goose StatementBegin
+-- SQLite can't change the collation of a column, so the tables are rebuilt
+-- with case-insensitive names.
+CREATE TABLE region_nocase (
+	psgc_code TEXT PRIMARY KEY,
+	name TEXT COLLATE NOCASE,
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO region_nocase SELECT psgc_code, name, population_2015, population_2020 FROM region;
+DROP TABLE region;
+ALTER TABLE region_nocase RENAME TO region;
+
+CREATE TABLE province_nocase (
+	psgc_code TEXT PRIMARY KEY,
+	reg_code TEXT,
+	name TEXT COLLATE NOCASE,
+	income_class TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO province_nocase SELECT
+	psgc_code, reg_code, name, income_class, population_2015, population_2020
+FROM province;
+DROP TABLE province;
+ALTER TABLE province_nocase RENAME TO province;
+
+CREATE TABLE city_muni_nocase (
+	psgc_code TEXT PRIMARY KEY,
+	prov_code TEXT,
+	name TEXT COLLATE NOCASE,
+	level TEXT,
+	city_class TEXT NOT NULL DEFAULT '',
+	income_class TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO city_muni_nocase SELECT
+	psgc_code, prov_code, name, level,
+	city_class, income_class, status, population_2015, population_2020
+FROM city_muni;
+DROP TABLE city_muni;
+ALTER TABLE city_muni_nocase RENAME TO city_muni;
+
+CREATE TABLE barangay_nocase (
+	psgc_code TEXT PRIMARY KEY,
+	citmun_code TEXT,
+	name TEXT COLLATE NOCASE,
+	urban_rural TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO barangay_nocase SELECT
+	psgc_code, citmun_code, name,
+	urban_rural, status, population_2015, population_2020
+FROM barangay;
+DROP TABLE barangay;
+ALTER TABLE barangay_nocase RENAME TO barangay;
+
+-- Parent code and level filters, psgc_code keeps the default sort order
+-- inside the index.
+CREATE INDEX province_reg_code_idx ON province (reg_code, psgc_code);
+CREATE INDEX city_muni_prov_code_idx ON city_muni (prov_code, psgc_code);
+CREATE INDEX city_muni_level_idx ON city_muni (level, psgc_code);
+CREATE INDEX barangay_citmun_code_idx ON barangay (citmun_code, psgc_code);
+
+-- Sorting by name
+CREATE INDEX region_name_idx ON region (name, psgc_code);
+CREATE INDEX province_name_idx ON province (name, psgc_code);
+CREATE INDEX city_muni_name_idx ON city_muni (name, psgc_code);
+CREATE INDEX barangay_name_idx ON barangay (name, psgc_code);
+
+-- Covering indexes for listing the names of the children of a parent
+-- without reading the table.
+CREATE INDEX province_reg_code_name_idx ON province (reg_code, name, psgc_code);
+CREATE INDEX city_muni_prov_code_name_idx ON city_muni (prov_code, name, psgc_code, level);
+CREATE INDEX barangay_citmun_code_name_idx ON barangay (citmun_code, name, psgc_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX barangay_citmun_code_name_idx;
+DROP INDEX city_muni_prov_code_name_idx;
+DROP INDEX province_reg_code_name_idx;
+DROP INDEX barangay_name_idx;
+DROP INDEX city_muni_name_idx;
+DROP INDEX province_name_idx;
+DROP INDEX region_name_idx;
+DROP INDEX barangay_citmun_code_idx;
+DROP INDEX city_muni_level_idx;
+DROP INDEX city_muni_prov_code_idx;
+DROP INDEX province_reg_code_idx;
+
+CREATE TABLE region_binary (
+	psgc_code TEXT PRIMARY KEY,
+	name TEXT,
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO region_binary SELECT psgc_code, name, population_2015, population_2020 FROM region;
+DROP TABLE region;
+ALTER TABLE region_binary RENAME TO region;
+
+CREATE TABLE province_binary (
+	psgc_code TEXT PRIMARY KEY,
+	reg_code TEXT,
+	name TEXT,
+	income_class TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO province_binary SELECT
+	psgc_code, reg_code, name, income_class, population_2015, population_2020
+FROM province;
+DROP TABLE province;
+ALTER TABLE province_binary RENAME TO province;
+
+CREATE TABLE city_muni_binary (
+	psgc_code TEXT PRIMARY KEY,
+	prov_code TEXT,
+	name TEXT,
+	level TEXT,
+	city_class TEXT NOT NULL DEFAULT '',
+	income_class TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO city_muni_binary SELECT
+	psgc_code, prov_code, name, level,
+	city_class, income_class, status, population_2015, population_2020
+FROM city_muni;
+DROP TABLE city_muni;
+ALTER TABLE city_muni_binary RENAME TO city_muni;
+
+CREATE TABLE barangay_binary (
+	psgc_code TEXT PRIMARY KEY,
+	citmun_code TEXT,
+	name TEXT,
+	urban_rural TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO barangay_binary SELECT
+	psgc_code, citmun_code, name,
+	urban_rural, status, population_2015, population_2020
+FROM barangay;
+DROP TABLE barangay;
+ALTER TABLE barangay_binary RENAME TO barangay;
+-- +goose StatementEnd
@@ -0,0 +1,126 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The tables are rebuilt parent first, so no table is dropped while another
+-- one references it. Independent cities and Pateros have no province, their
+-- prov_code becomes NULL.
+CREATE TABLE province_fk (
+	psgc_code TEXT PRIMARY KEY,
+	reg_code TEXT NOT NULL REFERENCES region (psgc_code) ON DELETE CASCADE,
+	name TEXT COLLATE NOCASE,
+	income_class TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO province_fk SELECT
+	psgc_code, reg_code, name, income_class, population_2015, population_2020
+FROM province;
+DROP TABLE province;
+ALTER TABLE province_fk RENAME TO province;
+
+CREATE TABLE city_muni_fk (
+	psgc_code TEXT PRIMARY KEY,
+	prov_code TEXT REFERENCES province (psgc_code) ON DELETE CASCADE,
+	name TEXT COLLATE NOCASE,
+	level TEXT,
+	city_class TEXT NOT NULL DEFAULT '',
+	income_class TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO city_muni_fk SELECT
+	psgc_code,
+	CASE WHEN prov_code IN (SELECT psgc_code FROM province) THEN prov_code END,
+	name, level,
+	city_class, income_class, status, population_2015, population_2020
+FROM city_muni;
+DROP TABLE city_muni;
+ALTER TABLE city_muni_fk RENAME TO city_muni;
+
+CREATE TABLE barangay_fk (
+	psgc_code TEXT PRIMARY KEY,
+	citmun_code TEXT NOT NULL REFERENCES city_muni (psgc_code) ON DELETE CASCADE,
+	name TEXT COLLATE NOCASE,
+	urban_rural TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO barangay_fk SELECT
+	psgc_code, citmun_code, name,
+	urban_rural, status, population_2015, population_2020
+FROM barangay;
+DROP TABLE barangay;
+ALTER TABLE barangay_fk RENAME TO barangay;
+
+-- Dropping the tables dropped their indexes
+CREATE INDEX province_reg_code_idx ON province (reg_code, psgc_code);
+CREATE INDEX city_muni_prov_code_idx ON city_muni (prov_code, psgc_code);
+CREATE INDEX city_muni_level_idx ON city_muni (level, psgc_code);
+CREATE INDEX barangay_citmun_code_idx ON barangay (citmun_code, psgc_code);
+
+CREATE INDEX province_name_idx ON province (name, psgc_code);
+CREATE INDEX city_muni_name_idx ON city_muni (name, psgc_code);
+CREATE INDEX barangay_name_idx ON barangay (name, psgc_code);
+
+CREATE INDEX province_reg_code_name_idx ON province (reg_code, name, psgc_code);
+CREATE INDEX city_muni_prov_code_name_idx ON city_muni (prov_code, name, psgc_code, level);
+CREATE INDEX barangay_citmun_code_name_idx ON barangay (citmun_code, name, psgc_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+-- Children first, so no table is dropped while another one references it.
+CREATE TABLE barangay_nofk (
+	psgc_code TEXT PRIMARY KEY,
+	citmun_code TEXT,
+	name TEXT COLLATE NOCASE,
+	urban_rural TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO barangay_nofk SELECT * FROM barangay;
+DROP TABLE barangay;
+ALTER TABLE barangay_nofk RENAME TO barangay;
+
+CREATE TABLE city_muni_nofk (
+	psgc_code TEXT PRIMARY KEY,
+	prov_code TEXT,
+	name TEXT COLLATE NOCASE,
+	level TEXT,
+	city_class TEXT NOT NULL DEFAULT '',
+	income_class TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO city_muni_nofk SELECT * FROM city_muni;
+DROP TABLE city_muni;
+ALTER TABLE city_muni_nofk RENAME TO city_muni;
+
+CREATE TABLE province_nofk (
+	psgc_code TEXT PRIMARY KEY,
+	reg_code TEXT,
+	name TEXT COLLATE NOCASE,
+	income_class TEXT NOT NULL DEFAULT '',
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+INSERT INTO province_nofk SELECT * FROM province;
+DROP TABLE province;
+ALTER TABLE province_nofk RENAME TO province;
+
+CREATE INDEX province_reg_code_idx ON province (reg_code, psgc_code);
+CREATE INDEX city_muni_prov_code_idx ON city_muni (prov_code, psgc_code);
+CREATE INDEX city_muni_level_idx ON city_muni (level, psgc_code);
+CREATE INDEX barangay_citmun_code_idx ON barangay (citmun_code, psgc_code);
+
+CREATE INDEX province_name_idx ON province (name, psgc_code);
+CREATE INDEX city_muni_name_idx ON city_muni (name, psgc_code);
+CREATE INDEX barangay_name_idx ON barangay (name, psgc_code);
+
+CREATE INDEX province_reg_code_name_idx ON province (reg_code, name, psgc_code);
+CREATE INDEX city_muni_prov_code_name_idx ON city_muni (prov_code, name, psgc_code, level);
+CREATE INDEX barangay_citmun_code_name_idx ON barangay (citmun_code, name, psgc_code);
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every unit of every level in one table. Units without a parent at the
+-- level right above, like independent cities, hang off their region.
+CREATE TABLE geo_unit (
+	psgc_code TEXT PRIMARY KEY,
+	name TEXT COLLATE NOCASE NOT NULL,
+	level TEXT NOT NULL,
+	parent_code TEXT REFERENCES geo_unit (psgc_code) ON DELETE CASCADE,
+	population_2015 INTEGER NOT NULL DEFAULT 0,
+	population_2020 INTEGER NOT NULL DEFAULT 0
+);
+CREATE INDEX geo_unit_parent_code_idx ON geo_unit (parent_code, psgc_code);
+CREATE INDEX geo_unit_level_idx ON geo_unit (level, psgc_code);
+CREATE INDEX geo_unit_name_idx ON geo_unit (name, psgc_code);
+
+-- One row for every (ancestor, descendant) pair, including each unit with
+-- itself at depth 0, so a subtree is a single index range.
+CREATE TABLE geo_unit_closure (
+	ancestor TEXT NOT NULL REFERENCES geo_unit (psgc_code) ON DELETE CASCADE,
+	descendant TEXT NOT NULL REFERENCES geo_unit (psgc_code) ON DELETE CASCADE,
+	depth INTEGER NOT NULL,
+	PRIMARY KEY (ancestor, descendant)
+) WITHOUT ROWID;
+CREATE INDEX geo_unit_closure_descendant_idx ON geo_unit_closure (descendant, depth);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE geo_unit_closure;
+DROP TABLE geo_unit;
+-- +goose StatementEnd
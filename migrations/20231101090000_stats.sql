@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The counts and urban/rural splits of the units below every unit, filled
+-- by the generator from the closure table.
+CREATE TABLE geo_unit_stats (
+	psgc_code TEXT PRIMARY KEY REFERENCES geo_unit (psgc_code) ON DELETE CASCADE,
+	regions INTEGER NOT NULL DEFAULT 0,
+	provinces INTEGER NOT NULL DEFAULT 0,
+	cities INTEGER NOT NULL DEFAULT 0,
+	municipalities INTEGER NOT NULL DEFAULT 0,
+	sgus INTEGER NOT NULL DEFAULT 0,
+	barangays INTEGER NOT NULL DEFAULT 0,
+	urban_barangays INTEGER NOT NULL DEFAULT 0,
+	rural_barangays INTEGER NOT NULL DEFAULT 0,
+	urban_population_2020 INTEGER NOT NULL DEFAULT 0,
+	rural_population_2020 INTEGER NOT NULL DEFAULT 0
+) WITHOUT ROWID;
+
+-- Every unit with its stats, as the API reads them
+CREATE VIEW geo_unit_summary AS
+SELECT
+	g.psgc_code, g.name, g.level,
+	s.regions, s.provinces, s.cities, s.municipalities, s.sgus, s.barangays,
+	g.population_2015, g.population_2020,
+	s.urban_barangays, s.rural_barangays, s.urban_population_2020, s.rural_population_2020
+FROM geo_unit g
+JOIN geo_unit_stats s ON s.psgc_code = g.psgc_code;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP VIEW geo_unit_summary;
+DROP TABLE geo_unit_stats;
+-- +goose StatementEnd
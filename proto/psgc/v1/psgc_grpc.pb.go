@@ -0,0 +1,267 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: proto/psgc/v1/psgc.proto
+
+package psgcv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PsgcService_GetUnit_FullMethodName   = "/psgc.v1.PsgcService/GetUnit"
+	PsgcService_ListUnits_FullMethodName = "/psgc.v1.PsgcService/ListUnits"
+	PsgcService_BatchGet_FullMethodName  = "/psgc.v1.PsgcService/BatchGet"
+	PsgcService_Ancestors_FullMethodName = "/psgc.v1.PsgcService/Ancestors"
+	PsgcService_Search_FullMethodName    = "/psgc.v1.PsgcService/Search"
+)
+
+// PsgcServiceClient is the client API for PsgcService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PsgcServiceClient interface {
+	// GetUnit returns a unit by PSGC code, NOT_FOUND for an unknown code.
+	GetUnit(ctx context.Context, in *GetUnitRequest, opts ...grpc.CallOption) (*Unit, error)
+	// ListUnits returns a page of units matching the filters.
+	ListUnits(ctx context.Context, in *ListUnitsRequest, opts ...grpc.CallOption) (*ListUnitsResponse, error)
+	// BatchGet returns many units by PSGC code in one call.
+	BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error)
+	// Ancestors returns the units above a unit, starting from its region.
+	Ancestors(ctx context.Context, in *AncestorsRequest, opts ...grpc.CallOption) (*AncestorsResponse, error)
+	// Search finds units by name or code, the most populous first.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+}
+
+type psgcServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPsgcServiceClient(cc grpc.ClientConnInterface) PsgcServiceClient {
+	return &psgcServiceClient{cc}
+}
+
+func (c *psgcServiceClient) GetUnit(ctx context.Context, in *GetUnitRequest, opts ...grpc.CallOption) (*Unit, error) {
+	out := new(Unit)
+	err := c.cc.Invoke(ctx, PsgcService_GetUnit_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *psgcServiceClient) ListUnits(ctx context.Context, in *ListUnitsRequest, opts ...grpc.CallOption) (*ListUnitsResponse, error) {
+	out := new(ListUnitsResponse)
+	err := c.cc.Invoke(ctx, PsgcService_ListUnits_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *psgcServiceClient) BatchGet(ctx context.Context, in *BatchGetRequest, opts ...grpc.CallOption) (*BatchGetResponse, error) {
+	out := new(BatchGetResponse)
+	err := c.cc.Invoke(ctx, PsgcService_BatchGet_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *psgcServiceClient) Ancestors(ctx context.Context, in *AncestorsRequest, opts ...grpc.CallOption) (*AncestorsResponse, error) {
+	out := new(AncestorsResponse)
+	err := c.cc.Invoke(ctx, PsgcService_Ancestors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *psgcServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, PsgcService_Search_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PsgcServiceServer is the server API for PsgcService service.
+// All implementations must embed UnimplementedPsgcServiceServer
+// for forward compatibility
+type PsgcServiceServer interface {
+	// GetUnit returns a unit by PSGC code, NOT_FOUND for an unknown code.
+	GetUnit(context.Context, *GetUnitRequest) (*Unit, error)
+	// ListUnits returns a page of units matching the filters.
+	ListUnits(context.Context, *ListUnitsRequest) (*ListUnitsResponse, error)
+	// BatchGet returns many units by PSGC code in one call.
+	BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error)
+	// Ancestors returns the units above a unit, starting from its region.
+	Ancestors(context.Context, *AncestorsRequest) (*AncestorsResponse, error)
+	// Search finds units by name or code, the most populous first.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	mustEmbedUnimplementedPsgcServiceServer()
+}
+
+// UnimplementedPsgcServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPsgcServiceServer struct {
+}
+
+func (UnimplementedPsgcServiceServer) GetUnit(context.Context, *GetUnitRequest) (*Unit, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUnit not implemented")
+}
+func (UnimplementedPsgcServiceServer) ListUnits(context.Context, *ListUnitsRequest) (*ListUnitsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUnits not implemented")
+}
+func (UnimplementedPsgcServiceServer) BatchGet(context.Context, *BatchGetRequest) (*BatchGetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method BatchGet not implemented")
+}
+func (UnimplementedPsgcServiceServer) Ancestors(context.Context, *AncestorsRequest) (*AncestorsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Ancestors not implemented")
+}
+func (UnimplementedPsgcServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedPsgcServiceServer) mustEmbedUnimplementedPsgcServiceServer() {}
+
+// UnsafePsgcServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PsgcServiceServer will
+// result in compilation errors.
+type UnsafePsgcServiceServer interface {
+	mustEmbedUnimplementedPsgcServiceServer()
+}
+
+func RegisterPsgcServiceServer(s grpc.ServiceRegistrar, srv PsgcServiceServer) {
+	s.RegisterService(&PsgcService_ServiceDesc, srv)
+}
+
+func _PsgcService_GetUnit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUnitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PsgcServiceServer).GetUnit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PsgcService_GetUnit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PsgcServiceServer).GetUnit(ctx, req.(*GetUnitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PsgcService_ListUnits_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnitsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PsgcServiceServer).ListUnits(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PsgcService_ListUnits_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PsgcServiceServer).ListUnits(ctx, req.(*ListUnitsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PsgcService_BatchGet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BatchGetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PsgcServiceServer).BatchGet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PsgcService_BatchGet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PsgcServiceServer).BatchGet(ctx, req.(*BatchGetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PsgcService_Ancestors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AncestorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PsgcServiceServer).Ancestors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PsgcService_Ancestors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PsgcServiceServer).Ancestors(ctx, req.(*AncestorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PsgcService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PsgcServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PsgcService_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PsgcServiceServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PsgcService_ServiceDesc is the grpc.ServiceDesc for PsgcService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PsgcService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "psgc.v1.PsgcService",
+	HandlerType: (*PsgcServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUnit",
+			Handler:    _PsgcService_GetUnit_Handler,
+		},
+		{
+			MethodName: "ListUnits",
+			Handler:    _PsgcService_ListUnits_Handler,
+		},
+		{
+			MethodName: "BatchGet",
+			Handler:    _PsgcService_BatchGet_Handler,
+		},
+		{
+			MethodName: "Ancestors",
+			Handler:    _PsgcService_Ancestors_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _PsgcService_Search_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/psgc/v1/psgc.proto",
+}